@@ -2,9 +2,9 @@ module github.com/i-sub135/i-sub-orm
 
 go 1.22.2
 
-require github.com/mattn/go-sqlite3 v1.14.32
-
 require (
-	github.com/DATA-DOG/go-sqlmock v1.5.2 // indirect
-	github.com/lib/pq v1.10.9 // indirect
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.32
 )
+
+require github.com/DATA-DOG/go-sqlmock v1.5.2
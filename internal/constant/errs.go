@@ -4,5 +4,19 @@ import "errors"
 
 var (
 	ErrDestination     = errors.New("destination must be pointer")
-	ErrDestinationType = errors.New("destination must be slice or struct")
+	ErrDestinationType = errors.New("destination must be struct, scalar, map or slice of them")
+	ErrEmptyValues     = errors.New("values must not be empty")
+	ErrModel           = errors.New("model must be pointer to struct")
+	ErrCursor          = errors.New("invalid cursor")
+	ErrCursorOrder     = errors.New("cursor pagination requires order by")
+	ErrCursorColumn    = errors.New("order by column not found in destination")
+	ErrNamedParam      = errors.New("missing named parameter")
+	ErrScalarColumns   = errors.New("scalar destination requires exactly one column")
+	ErrStrictScan      = errors.New("result columns do not match destination")
+	ErrConvert         = errors.New("cannot convert value")
+	ErrInValue         = errors.New("IN condition value must be a slice or subquery")
+	ErrSource          = errors.New("source must be a table name or *Query")
+	ErrCondition       = errors.New("unsupported condition type")
+	ErrNoWhere         = errors.New("update and delete require a where condition")
+	ErrPageSize        = errors.New("page size must be at least 1")
 )
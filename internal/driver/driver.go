@@ -8,6 +8,7 @@ const (
 	MySQL    Driver = "mysql"
 	SQLite   Driver = "sqlite3"
 	MSSQL    Driver = "sqlserver"
+	Oracle   Driver = "oracle"
 )
 
 // String returns the string representation of the driver
@@ -15,10 +16,26 @@ func (d Driver) String() string {
 	return string(d)
 }
 
+// Normalize returns the driver constant for name, resolving the aliases
+// accepted by the placeholder rebinding: "postgresql", "mssql" and "godror".
+// Other names are returned unchanged.
+func Normalize(name string) Driver {
+	switch name {
+	case "postgresql":
+		return Postgres
+	case "mssql":
+		return MSSQL
+	case "godror":
+		return Oracle
+	default:
+		return Driver(name)
+	}
+}
+
 // IsValid checks if the driver is a valid/supported driver
 func (d Driver) IsValid() bool {
 	switch d {
-	case Postgres, MySQL, SQLite, MSSQL:
+	case Postgres, MySQL, SQLite, MSSQL, Oracle:
 		return true
 	default:
 		return false
@@ -1,12 +1,14 @@
 package executor
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 )
 
 type Executor struct {
 	DB *sql.DB
+	Tx *sql.Tx // set when the executor runs inside a transaction
 }
 
 // NewExecutor creates a new Executor with a database connection.
@@ -20,7 +22,43 @@ func NewExecutor(driver, dsn string) (*Executor, error) {
 
 // Query executes a query and returns the resulting rows.
 func (e *Executor) Query(query string, args ...any) (*sql.Rows, error) {
-	return e.DB.Query(query, args...)
+	return e.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext executes a query bound to ctx and returns the resulting rows.
+func (e *Executor) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if e.Tx != nil {
+		return e.Tx.QueryContext(ctx, query, args...)
+	}
+	return e.DB.QueryContext(ctx, query, args...)
+}
+
+// ExecContext executes a statement bound to ctx without returning any rows.
+func (e *Executor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if e.Tx != nil {
+		return e.Tx.ExecContext(ctx, query, args...)
+	}
+	return e.DB.ExecContext(ctx, query, args...)
+}
+
+// Begin starts a transaction and returns an Executor that runs every
+// statement inside it.
+func (e *Executor) Begin(ctx context.Context) (*Executor, error) {
+	tx, err := e.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction : %w", err)
+	}
+	return &Executor{DB: e.DB, Tx: tx}, nil
+}
+
+// Commit commits the transaction started by Begin.
+func (e *Executor) Commit() error {
+	return e.Tx.Commit()
+}
+
+// Rollback aborts the transaction started by Begin.
+func (e *Executor) Rollback() error {
+	return e.Tx.Rollback()
 }
 
 // Close closes the database connection.
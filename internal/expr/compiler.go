@@ -2,52 +2,363 @@ package expr
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
+
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+	"github.com/i-sub135/i-sub-orm/internal/driver"
 )
 
+// compiler carries the target dialect while compiling nested conditions,
+// and the first invalid condition it met.
+type compiler struct {
+	driver driver.Driver
+	err    error
+}
+
 // compile will compile the given condition into a SQL string and its arguments.
+// Dialect specific operators fall back to their portable form.
 func Compile(condition any) (string, []any) {
+	return CompileFor(condition, "")
+}
+
+// CompileFor is like Compile but renders dialect specific operators (such as
+// ILIKE) for the given driver or one of its aliases.
+func CompileFor(condition any, d driver.Driver) (string, []any) {
+	sql, args, _ := CompileErr(condition, d)
+	return sql, args
+}
+
+// CompileErr is like CompileFor but also reports an invalid condition, such
+// as an IN Cond whose value is not a slice or a type the compiler does not
+// know, instead of compiling it.
+func CompileErr(condition any, d driver.Driver) (string, []any, error) {
+	c := &compiler{driver: driver.Normalize(string(d))}
+	sql, args := c.compile(condition)
+	if c.err != nil {
+		return "", nil, c.err
+	}
+	return sql, args, nil
+}
+
+func (c *compiler) compile(condition any) (string, []any) {
 	switch cond := condition.(type) {
 	case Eq:
-		return builCompair(cond, "=")
+		return c.builCompair(cond, "=")
 	case Neq:
-		return builCompair(cond, "!=")
+		return c.builCompair(cond, "!=")
 	case Gt:
-		return builCompair(cond, ">")
+		return c.builCompair(cond, ">")
+	case Gte:
+		return c.builCompair(cond, ">=")
 	case Lt:
-		return builCompair(cond, "<")
+		return c.builCompair(cond, "<")
+	case Lte:
+		return c.builCompair(cond, "<=")
+	case Like:
+		return c.builCompair(cond, "LIKE")
+	case ILike:
+		return c.buildILike(cond)
 	case In:
-		return buildIN(cond)
+		return c.buildIN(cond, "IN")
+	case NotIn:
+		return c.buildIN(cond, "NOT IN")
+	case Between:
+		return buildBetween(cond)
+	case IsNull:
+		return buildIsNull(cond)
+	case Contains:
+		return c.builCompair(cond, "@>")
+	case Overlap:
+		return c.builCompair(cond, "&&")
+	case Any:
+		return c.buildAny(cond)
+	case Cond:
+		return c.buildCond(cond)
+	case Conds:
+		group := make(And, len(cond))
+		for i, cd := range cond {
+			group[i] = cd
+		}
+		return c.buildGroup(group, " AND ")
+	case And:
+		return c.buildGroup(cond, " AND ")
+	case Or:
+		return c.buildGroup(cond, " OR ")
+	case Not:
+		sql, args := c.buildGroup(cond, " AND ")
+		if sql == "" {
+			return "", nil
+		}
+		return "NOT " + wrap(sql), args
 	default:
+		c.setErr(fmt.Errorf("%w: %T", constant.ErrCondition, condition))
 		return "", nil
 	}
 
 }
 
 // builCompair builds comparison expressions like "field = ?" and returns the SQL string and arguments.
-func builCompair(data map[string]any, operator string) (string, []any) {
+func (c *compiler) builCompair(data map[string]any, operator string) (string, []any) {
 
 	parts := make([]string, 0, len(data))
 	args := make([]any, 0, len(data))
-	for k, v := range data {
-		parts = append(parts, fmt.Sprintf("%s %s ?", k, operator))
-		args = append(args, v)
+	for _, k := range sortedKeys(data) {
+		sql, a := c.compare(k, operator, data[k])
+		parts = append(parts, sql)
+		args = append(args, a...)
 	}
 
 	return strings.Join(parts, " AND "), args
 
 }
 
+// buildILike builds case-insensitive LIKE expressions. Postgres has a native
+// ILIKE; other engines compare both sides lowercased.
+func (c *compiler) buildILike(data map[string]any) (string, []any) {
+	if c.driver == driver.Postgres {
+		return c.builCompair(data, "ILIKE")
+	}
+
+	parts := make([]string, 0, len(data))
+	args := make([]any, 0, len(data))
+	for _, k := range sortedKeys(data) {
+		parts = append(parts, fmt.Sprintf("LOWER(%s) LIKE LOWER(?)", k))
+		args = append(args, data[k])
+	}
+	return strings.Join(parts, " AND "), args
+}
+
 // buildIN builds IN expressions like "field IN (?, ?, ?)" and returns the SQL string and arguments.
-func buildIN(data map[string][]any) (string, []any) {
+// A single Subquery element compiles to "field IN (SELECT ...)". An empty
+// list never matches for IN and always matches for NOT IN, so it compiles
+// to "1 = 0" or "1 = 1" instead of the invalid "IN ()".
+func (c *compiler) buildIN(data map[string][]any, operator string) (string, []any) {
 	parts := make([]string, 0, len(data))
 	args := make([]any, 0)
 
-	for k, v := range data {
+	for _, k := range sortedKeys(data) {
+		v := data[k]
+		if len(v) == 1 {
+			if sub, ok := v[0].(Subquery); ok {
+				sql, a := c.subquery(sub)
+				parts = append(parts, fmt.Sprintf("%s %s (%s)", k, operator, sql))
+				args = append(args, a...)
+				continue
+			}
+		}
+		if len(v) == 0 {
+			if operator == "IN" {
+				parts = append(parts, "1 = 0")
+			} else {
+				parts = append(parts, "1 = 1")
+			}
+			continue
+		}
 		placeholders := strings.Repeat("?,", len(v))
 		placeholders = strings.TrimRight(placeholders, ",")
-		parts = append(parts, fmt.Sprintf("%s IN (%s)", k, placeholders))
+		parts = append(parts, fmt.Sprintf("%s %s (%s)", k, operator, placeholders))
 		args = append(args, v...)
 	}
 	return strings.Join(parts, " AND "), args
 }
+
+// buildAny builds "field = ANY(?)" with the slice bound as one array value.
+// Outside Postgres a slice expands to "field IN (?, ?)" instead; a Subquery
+// compiles to "field = ANY(SELECT ...)" on Postgres and "field IN (SELECT ...)"
+// elsewhere.
+func (c *compiler) buildAny(data map[string]any) (string, []any) {
+	if c.driver != driver.Postgres {
+		in := make(map[string][]any, len(data))
+		for k, v := range data {
+			in[k] = anySlice(v)
+		}
+		return c.buildIN(in, "IN")
+	}
+
+	parts := make([]string, 0, len(data))
+	args := make([]any, 0, len(data))
+	for _, k := range sortedKeys(data) {
+		if sub, ok := data[k].(Subquery); ok {
+			sql, a := c.subquery(sub)
+			parts = append(parts, fmt.Sprintf("%s = ANY(%s)", k, sql))
+			args = append(args, a...)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s = ANY(?)", k))
+		args = append(args, data[k])
+	}
+	return strings.Join(parts, " AND "), args
+}
+
+// anySlice returns the elements of slice v as []any; a Subquery or any other
+// value is returned as its single element.
+func anySlice(v any) []any {
+	if _, ok := v.(Subquery); ok {
+		return []any{v}
+	}
+	if values, ok := sliceValues(v); ok {
+		return values
+	}
+	return []any{v}
+}
+
+// sliceValues returns the elements of a slice of any type as []any; ok is
+// false when v is not a slice. []byte is a single value, not a slice.
+func sliceValues(v any) ([]any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out, true
+}
+
+// buildBetween builds "field BETWEEN ? AND ?" expressions.
+func buildBetween(data map[string][2]any) (string, []any) {
+	parts := make([]string, 0, len(data))
+	args := make([]any, 0, len(data)*2)
+
+	for _, k := range sortedKeys(data) {
+		parts = append(parts, fmt.Sprintf("%s BETWEEN ? AND ?", k))
+		args = append(args, data[k][0], data[k][1])
+	}
+	return strings.Join(parts, " AND "), args
+}
+
+// buildIsNull builds "field IS NULL" (true) or "field IS NOT NULL" (false) expressions.
+func buildIsNull(data map[string]bool) (string, []any) {
+	parts := make([]string, 0, len(data))
+
+	for _, k := range sortedKeys(data) {
+		if data[k] {
+			parts = append(parts, k+" IS NULL")
+		} else {
+			parts = append(parts, k+" IS NOT NULL")
+		}
+	}
+	return strings.Join(parts, " AND "), nil
+}
+
+// buildCond builds a single comparison in the operator given by the caller.
+// IN/NOT IN expand a slice of any element type or take a Subquery; any other
+// value is reported as invalid. IS NULL/IS NOT NULL take no value.
+func (c *compiler) buildCond(cond Cond) (string, []any) {
+	op := strings.ToUpper(strings.TrimSpace(cond.Op))
+	switch op {
+	case "IN", "NOT IN":
+		values, ok := sliceValues(cond.Value)
+		if sub, isSub := cond.Value.(Subquery); isSub {
+			values, ok = []any{sub}, true
+		}
+		if !ok {
+			c.setErr(fmt.Errorf("%w: %s %s %T", constant.ErrInValue, cond.Column, op, cond.Value))
+			return "", nil
+		}
+		return c.buildIN(map[string][]any{cond.Column: values}, op)
+	case "IS NULL", "IS NOT NULL":
+		return cond.Column + " " + op, nil
+	default:
+		return c.compare(cond.Column, cond.Op, cond.Value)
+	}
+}
+
+// compare renders "column op ?" with value as its argument, "column op other"
+// when value is a Col reference, or "column op (SELECT ...)" with the
+// subquery arguments when value is a Subquery.
+func (c *compiler) compare(column, operator string, value any) (string, []any) {
+	switch v := value.(type) {
+	case Col:
+		return fmt.Sprintf("%s %s %s", column, operator, v), nil
+	case Subquery:
+		sql, args := c.subquery(v)
+		return fmt.Sprintf("%s %s (%s)", column, operator, sql), args
+	}
+	return fmt.Sprintf("%s %s ?", column, operator), []any{value}
+}
+
+// subquery returns the SQL and arguments of sub. A sub that failed to build
+// reports it through an Err method, which fails the whole condition.
+func (c *compiler) subquery(sub Subquery) (string, []any) {
+	if e, ok := sub.(interface{ Err() error }); ok {
+		c.setErr(e.Err())
+	}
+	return sub.ToSQL()
+}
+
+// setErr keeps the first invalid condition met while compiling
+func (c *compiler) setErr(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// sortedKeys returns the keys of data in ascending order, so a map based
+// condition always compiles to the same SQL text.
+func sortedKeys[V any](data map[string]V) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// buildGroup compiles each condition and joins them with sep, wrapping the
+// result in parentheses when it holds more than one condition.
+func (c *compiler) buildGroup(conds []any, sep string) (string, []any) {
+	parts := make([]string, 0, len(conds))
+	args := make([]any, 0)
+
+	for _, cond := range conds {
+		sql, a := c.compile(cond)
+		if sql == "" {
+			continue
+		}
+		// a map with several keys compiles to "a AND b"; keep it grouped
+		if v := reflect.ValueOf(cond); v.Kind() == reflect.Map && v.Len() > 1 {
+			sql = "(" + sql + ")"
+		}
+		parts = append(parts, sql)
+		args = append(args, a...)
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", nil
+	case 1:
+		return parts[0], args
+	default:
+		return "(" + strings.Join(parts, sep) + ")", args
+	}
+}
+
+// wrap puts sql in parentheses unless it already is a single group.
+func wrap(sql string) string {
+	if strings.HasPrefix(sql, "(") && strings.HasSuffix(sql, ")") && closesAtEnd(sql) {
+		return sql
+	}
+	return "(" + sql + ")"
+}
+
+// closesAtEnd reports whether the parenthesis opened at sql[0] is the one
+// closed by the last byte.
+func closesAtEnd(sql string) bool {
+	depth := 0
+	for i := 0; i < len(sql); i++ {
+		switch sql[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i == len(sql)-1
+			}
+		}
+	}
+	return false
+}
@@ -1,9 +1,13 @@
 package expr_test
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+	"github.com/i-sub135/i-sub-orm/internal/driver"
 	"github.com/i-sub135/i-sub-orm/internal/expr"
 )
 
@@ -23,8 +27,8 @@ func TestCompile_Eq(t *testing.T) {
 		{
 			name:     "multiple fields equality",
 			input:    expr.Eq{"name": "John", "age": 30},
-			wantSQL:  "", // Will check both fields are present
-			wantArgs: []any{"John", 30},
+			wantSQL:  "age = ? AND name = ?",
+			wantArgs: []any{30, "John"},
 		},
 		{
 			name:     "integer value",
@@ -40,24 +44,11 @@ func TestCompile_Eq(t *testing.T) {
 
 			fmt.Println("Generated SQL:", sql)
 			fmt.Println("Arguments:", args)
-			// For multiple fields, just check all args are present
-			if len(tt.input) > 1 {
-				if len(args) != len(tt.wantArgs) {
-					t.Errorf("Compile() args count = %v, want %v", len(args), len(tt.wantArgs))
-				}
-				// Check SQL contains all field names and operators
-				for k := range tt.input {
-					if !contains(sql, k+" = ?") {
-						t.Errorf("Compile() sql = %v, should contain '%s = ?'", sql, k)
-					}
-				}
-			} else {
-				if sql != tt.wantSQL {
-					t.Errorf("Compile() sql = %v, want %v", sql, tt.wantSQL)
-				}
-				if !equalArgs(args, tt.wantArgs) {
-					t.Errorf("Compile() args = %v, want %v", args, tt.wantArgs)
-				}
+			if sql != tt.wantSQL {
+				t.Errorf("Compile() sql = %v, want %v", sql, tt.wantSQL)
+			}
+			if !equalArgs(args, tt.wantArgs) {
+				t.Errorf("Compile() args = %v, want %v", args, tt.wantArgs)
 			}
 		})
 	}
@@ -79,8 +70,8 @@ func TestCompile_Neq(t *testing.T) {
 		{
 			name:     "multiple fields not equal",
 			input:    expr.Neq{"status": "inactive", "deleted": true},
-			wantSQL:  "",
-			wantArgs: []any{"inactive", true},
+			wantSQL:  "deleted != ? AND status != ?",
+			wantArgs: []any{true, "inactive"},
 		},
 	}
 
@@ -88,22 +79,11 @@ func TestCompile_Neq(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			sql, args := expr.Compile(tt.input)
 
-			if len(tt.input) > 1 {
-				if len(args) != len(tt.wantArgs) {
-					t.Errorf("Compile() args count = %v, want %v", len(args), len(tt.wantArgs))
-				}
-				for k := range tt.input {
-					if !contains(sql, k+" != ?") {
-						t.Errorf("Compile() sql = %v, should contain '%s != ?'", sql, k)
-					}
-				}
-			} else {
-				if sql != tt.wantSQL {
-					t.Errorf("Compile() sql = %v, want %v", sql, tt.wantSQL)
-				}
-				if !equalArgs(args, tt.wantArgs) {
-					t.Errorf("Compile() args = %v, want %v", args, tt.wantArgs)
-				}
+			if sql != tt.wantSQL {
+				t.Errorf("Compile() sql = %v, want %v", sql, tt.wantSQL)
+			}
+			if !equalArgs(args, tt.wantArgs) {
+				t.Errorf("Compile() args = %v, want %v", args, tt.wantArgs)
 			}
 		})
 	}
@@ -220,6 +200,379 @@ func TestCompile_In(t *testing.T) {
 	}
 }
 
+func TestCompile_Operators(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "greater than or equal",
+			input:    expr.Gte{"created_at": "2024-01-01"},
+			wantSQL:  "created_at >= ?",
+			wantArgs: []any{"2024-01-01"},
+		},
+		{
+			name:     "less than or equal",
+			input:    expr.Lte{"age": 65},
+			wantSQL:  "age <= ?",
+			wantArgs: []any{65},
+		},
+		{
+			name:     "like",
+			input:    expr.Like{"name": "jo%"},
+			wantSQL:  "name LIKE ?",
+			wantArgs: []any{"jo%"},
+		},
+		{
+			name:     "ilike portable fallback",
+			input:    expr.ILike{"name": "jo%"},
+			wantSQL:  "LOWER(name) LIKE LOWER(?)",
+			wantArgs: []any{"jo%"},
+		},
+		{
+			name:     "between",
+			input:    expr.Between{"age": {18, 65}},
+			wantSQL:  "age BETWEEN ? AND ?",
+			wantArgs: []any{18, 65},
+		},
+		{
+			name:     "is null",
+			input:    expr.IsNull{"deleted_at": true},
+			wantSQL:  "deleted_at IS NULL",
+			wantArgs: nil,
+		},
+		{
+			name:     "is not null",
+			input:    expr.IsNull{"deleted_at": false},
+			wantSQL:  "deleted_at IS NOT NULL",
+			wantArgs: nil,
+		},
+		{
+			name:     "not in",
+			input:    expr.NotIn{"id": []any{1, 2}},
+			wantSQL:  "id NOT IN (?,?)",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "empty in never matches",
+			input:    expr.In{"id": []any{}},
+			wantSQL:  "1 = 0",
+			wantArgs: []any{},
+		},
+		{
+			name:     "empty not in always matches",
+			input:    expr.NotIn{"id": nil},
+			wantSQL:  "1 = 1",
+			wantArgs: []any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := expr.Compile(tt.input)
+
+			if sql != tt.wantSQL {
+				t.Errorf("Compile() sql = %v, want %v", sql, tt.wantSQL)
+			}
+			if !equalArgs(args, tt.wantArgs) {
+				t.Errorf("Compile() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCompileFor_ILike(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  driver.Driver
+		wantSQL string
+	}{
+		{name: "postgres native ilike", driver: driver.Postgres, wantSQL: "name ILIKE ?"},
+		{name: "postgresql alias", driver: "postgresql", wantSQL: "name ILIKE ?"},
+		{name: "mysql fallback", driver: driver.MySQL, wantSQL: "LOWER(name) LIKE LOWER(?)"},
+		{name: "sqlite fallback", driver: driver.SQLite, wantSQL: "LOWER(name) LIKE LOWER(?)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := expr.CompileFor(expr.ILike{"name": "%doe"}, tt.driver)
+
+			if sql != tt.wantSQL {
+				t.Errorf("CompileFor() sql = %v, want %v", sql, tt.wantSQL)
+			}
+			if !equalArgs(args, []any{"%doe"}) {
+				t.Errorf("CompileFor() args = %v, want [%%doe]", args)
+			}
+		})
+	}
+}
+
+func TestCompileFor_Arrays(t *testing.T) {
+	tags := []string{"go", "sql"}
+	ids := []int64{1, 2}
+	sub := subquery{sql: "SELECT user_id FROM orders WHERE status = ?", args: []any{"paid"}}
+
+	tests := []struct {
+		name     string
+		driver   driver.Driver
+		input    any
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "contains",
+			driver:   driver.Postgres,
+			input:    expr.Contains{"tags": tags},
+			wantSQL:  "tags @> ?",
+			wantArgs: []any{tags},
+		},
+		{
+			name:     "overlap",
+			driver:   driver.Postgres,
+			input:    expr.And{expr.Overlap{"tags": tags}, expr.Eq{"active": true}},
+			wantSQL:  "(tags && ? AND active = ?)",
+			wantArgs: []any{tags, true},
+		},
+		{
+			name:     "any binds one array",
+			driver:   driver.Postgres,
+			input:    expr.Any{"id": ids},
+			wantSQL:  "id = ANY(?)",
+			wantArgs: []any{ids},
+		},
+		{
+			name:     "any subquery",
+			driver:   driver.Postgres,
+			input:    expr.Any{"id": sub},
+			wantSQL:  "id = ANY(SELECT user_id FROM orders WHERE status = ?)",
+			wantArgs: []any{"paid"},
+		},
+		{
+			name:     "any expands outside postgres",
+			driver:   driver.MySQL,
+			input:    expr.Any{"id": ids},
+			wantSQL:  "id IN (?,?)",
+			wantArgs: []any{int64(1), int64(2)},
+		},
+		{
+			name:     "any empty outside postgres",
+			driver:   driver.SQLite,
+			input:    expr.Any{"id": []int64{}},
+			wantSQL:  "1 = 0",
+			wantArgs: []any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := expr.CompileFor(tt.input, tt.driver)
+
+			if sql != tt.wantSQL {
+				t.Errorf("CompileFor() sql = %v, want %v", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("CompileFor() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCompile_Groups(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "OR with nested AND",
+			input:    expr.Or{expr.Eq{"status": "active"}, expr.And{expr.Eq{"role": "admin"}, expr.Eq{"active": true}}},
+			wantSQL:  "(status = ? OR (role = ? AND active = ?))",
+			wantArgs: []any{"active", "admin", true},
+		},
+		{
+			name:     "AND of single condition is not wrapped",
+			input:    expr.And{expr.Gt{"age": 18}},
+			wantSQL:  "age > ?",
+			wantArgs: []any{18},
+		},
+		{
+			name:     "NOT single condition",
+			input:    expr.Not{expr.In{"id": []any{1, 2}}},
+			wantSQL:  "NOT (id IN (?,?))",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "NOT of OR keeps single group",
+			input:    expr.Not{expr.Or{expr.Eq{"a": 1}, expr.Eq{"b": 2}}},
+			wantSQL:  "NOT (a = ? OR b = ?)",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "empty group",
+			input:    expr.Or{},
+			wantSQL:  "",
+			wantArgs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := expr.Compile(tt.input)
+
+			if sql != tt.wantSQL {
+				t.Errorf("Compile() sql = %v, want %v", sql, tt.wantSQL)
+			}
+			if !equalArgs(args, tt.wantArgs) {
+				t.Errorf("Compile() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCompile_Deterministic(t *testing.T) {
+	input := expr.And{
+		expr.Eq{"status": "active", "role": "admin", "age": 30, "country": "ID"},
+		expr.In{"id": []any{1, 2}, "group_id": []any{3}},
+	}
+	wantSQL, wantArgs := expr.Compile(input)
+
+	for i := 0; i < 50; i++ {
+		sql, args := expr.Compile(input)
+		if sql != wantSQL {
+			t.Fatalf("Compile() sql = %v, want %v", sql, wantSQL)
+		}
+		if !equalArgs(args, wantArgs) {
+			t.Fatalf("Compile() args = %v, want %v", args, wantArgs)
+		}
+	}
+
+	want := "((age = ? AND country = ? AND role = ? AND status = ?) AND (group_id IN (?) AND id IN (?,?)))"
+	if wantSQL != want {
+		t.Errorf("Compile() sql = %v, want %v", wantSQL, want)
+	}
+}
+
+func TestCompile_Conds(t *testing.T) {
+	sql, args := expr.Compile(expr.Conds{
+		{Column: "status", Op: "=", Value: "active"},
+		{Column: "age", Op: ">=", Value: 18},
+		{Column: "id", Op: "in", Value: []any{1, 2}},
+		{Column: "deleted_at", Op: "IS NULL"},
+	})
+
+	wantSQL := "(status = ? AND age >= ? AND id IN (?,?) AND deleted_at IS NULL)"
+	if sql != wantSQL {
+		t.Errorf("Compile() sql = %v, want %v", sql, wantSQL)
+	}
+	if !equalArgs(args, []any{"active", 18, 1, 2}) {
+		t.Errorf("Compile() args = %v, want [active 18 1 2]", args)
+	}
+}
+
+func TestCompile_CondTypedSlice(t *testing.T) {
+	sql, args, err := expr.CompileErr(expr.Conds{
+		{Column: "id", Op: "IN", Value: []int64{1, 2}},
+		{Column: "role", Op: "NOT IN", Value: []string{"guest"}},
+	}, "")
+	if err != nil {
+		t.Fatalf("CompileErr() error = %v", err)
+	}
+
+	wantSQL := "(id IN (?,?) AND role NOT IN (?))"
+	if sql != wantSQL {
+		t.Errorf("CompileErr() sql = %v, want %v", sql, wantSQL)
+	}
+	if !equalArgs(args, []any{int64(1), int64(2), "guest"}) {
+		t.Errorf("CompileErr() args = %v, want [1 2 guest]", args)
+	}
+
+	for _, value := range []any{7, "1,2", nil} {
+		_, _, err := expr.CompileErr(expr.Or{expr.Eq{"a": 1}, expr.Cond{Column: "id", Op: "IN", Value: value}}, "")
+		if !errors.Is(err, constant.ErrInValue) {
+			t.Errorf("CompileErr(IN %v) error = %v, want ErrInValue", value, err)
+		}
+	}
+}
+
+func TestCompileErr_UnsupportedCond(t *testing.T) {
+	for _, cond := range []any{map[string]any{"id": 1}, &expr.Eq{"id": 1}, expr.And{expr.Eq{"a": 1}, 42}} {
+		if _, _, err := expr.CompileErr(cond, ""); !errors.Is(err, constant.ErrCondition) {
+			t.Errorf("CompileErr(%T) error = %v, want ErrCondition", cond, err)
+		}
+	}
+}
+
+func TestCompile_Col(t *testing.T) {
+	sql, args := expr.Compile(expr.Eq{"posts.user_id": expr.Col("users.id"), "posts.status": "draft"})
+
+	wantSQL := "posts.status = ? AND posts.user_id = users.id"
+	if sql != wantSQL {
+		t.Errorf("Compile() sql = %v, want %v", sql, wantSQL)
+	}
+	if !equalArgs(args, []any{"draft"}) {
+		t.Errorf("Compile() args = %v, want [draft]", args)
+	}
+}
+
+type subquery struct {
+	sql  string
+	args []any
+}
+
+func (s subquery) ToSQL() (string, []any) { return s.sql, s.args }
+
+func TestCompile_Subquery(t *testing.T) {
+	sub := subquery{sql: "SELECT user_id FROM orders WHERE status = ?", args: []any{"paid"}}
+
+	tests := []struct {
+		name     string
+		input    any
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "IN subquery",
+			input:    expr.And{expr.Eq{"active": true}, expr.In{"id": {sub}}},
+			wantSQL:  "(active = ? AND id IN (SELECT user_id FROM orders WHERE status = ?))",
+			wantArgs: []any{true, "paid"},
+		},
+		{
+			name:     "NOT IN subquery",
+			input:    expr.NotIn{"id": {sub}},
+			wantSQL:  "id NOT IN (SELECT user_id FROM orders WHERE status = ?)",
+			wantArgs: []any{"paid"},
+		},
+		{
+			name:     "comparison with subquery",
+			input:    expr.Gt{"total": sub},
+			wantSQL:  "total > (SELECT user_id FROM orders WHERE status = ?)",
+			wantArgs: []any{"paid"},
+		},
+		{
+			name:     "ordered IN subquery",
+			input:    expr.Cond{Column: "id", Op: "IN", Value: sub},
+			wantSQL:  "id IN (SELECT user_id FROM orders WHERE status = ?)",
+			wantArgs: []any{"paid"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := expr.Compile(tt.input)
+
+			if sql != tt.wantSQL {
+				t.Errorf("Compile() sql = %v, want %v", sql, tt.wantSQL)
+			}
+			if !equalArgs(args, tt.wantArgs) {
+				t.Errorf("Compile() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
 func TestCompile_UnknownType(t *testing.T) {
 	t.Run("unknown expression type returns empty", func(t *testing.T) {
 		sql, args := expr.Compile("invalid")
@@ -234,19 +587,6 @@ func TestCompile_UnknownType(t *testing.T) {
 }
 
 // Helper functions
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || containsMiddle(s, substr)))
-}
-
-func containsMiddle(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
-}
-
 func equalArgs(a, b []any) bool {
 	if len(a) != len(b) {
 		return false
@@ -1,12 +1,68 @@
 package expr
 
-// Eq  => equality ("=")
-// Neq => not equal ("!=")
-// Gt  => greater than
-// Lt  => less than
-// In  => IN (...)
+// Eq      => equality ("=")
+// Neq     => not equal ("!=")
+// Gt      => greater than
+// Gte     => greater than or equal
+// Lt      => less than
+// Lte     => less than or equal
+// Like    => LIKE pattern
+// ILike   => case-insensitive LIKE (LOWER(...) LIKE LOWER(?) outside Postgres)
+// In      => IN (...)
+// NotIn   => NOT IN (...)
+// Between => BETWEEN ? AND ?
+// IsNull  => IS NULL (true) / IS NOT NULL (false)
+// Contains => array contains ("@>"), Postgres only
+// Overlap  => arrays share an element ("&&"), Postgres only
+// Any      => = ANY(?) on Postgres, IN (...) elsewhere
 type Eq map[string]any
 type Neq map[string]any
 type Gt map[string]any
+type Gte map[string]any
 type Lt map[string]any
+type Lte map[string]any
+type Like map[string]any
+type ILike map[string]any
 type In map[string][]any
+type NotIn map[string][]any
+type Between map[string][2]any
+type IsNull map[string]bool
+type Contains map[string]any
+type Overlap map[string]any
+type Any map[string]any
+
+// Col references another column instead of binding a value, e.g. in a join:
+//
+//	Eq{"posts.user_id": Col("users.id")} => posts.user_id = users.id
+type Col string
+
+// Subquery is a query usable as a value, such as *orm.Query:
+//
+//	In{"user_id": {sub}} => user_id IN (SELECT ...)
+//	Eq{"total": sub}     => total = (SELECT ...)
+//
+// Its SQL uses "?" placeholders; the arguments are merged in order. When it
+// also has an Err() error method, a non-nil error fails the condition.
+type Subquery interface {
+	ToSQL() (string, []any)
+}
+
+// Map based conditions compile their columns in sorted order. Cond keeps
+// the exact operator and Conds the exact order given by the caller:
+//
+//	Conds{{"status", "=", "active"}, {"age", ">=", 18}}
+//	=> (status = ? AND age >= ?)
+type Cond struct {
+	Column string
+	Op     string
+	Value  any
+}
+type Conds []Cond
+
+// And => (a AND b)
+// Or  => (a OR b)
+// Not => NOT (a AND b)
+// Their elements are any expression, including nested And/Or/Not.
+type And []any
+type Or []any
+type Not []any
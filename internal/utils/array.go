@@ -0,0 +1,232 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+)
+
+// isArrayType reports whether t is a slice mapped to a Postgres array:
+// strings, bools, integers and floats. []byte stays a bytea value.
+func isArrayType(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	switch t.Elem().Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// arrayScan returns the scan func of a slice field of type t for a Postgres
+// array column: the array text is parsed into a new slice, NULL leaves a
+// nil slice and NULL elements their zero value.
+func arrayScan(t reflect.Type) func(src any) (any, error) {
+	return func(src any) (any, error) {
+		var text string
+		switch v := src.(type) {
+		case nil:
+			return nil, nil
+		case []byte:
+			text = string(v)
+		case string:
+			text = v
+		default:
+			return nil, fmt.Errorf("%w: %T %v to %s", constant.ErrConvert, src, src, t)
+		}
+
+		elems, err := ParseArray(text)
+		if err != nil {
+			return nil, err
+		}
+		out := reflect.MakeSlice(t, len(elems), len(elems))
+		for i, e := range elems {
+			if e == nil {
+				continue
+			}
+			if err := setArrayElem(out.Index(i), *e); err != nil {
+				return nil, err
+			}
+		}
+		return out.Interface(), nil
+	}
+}
+
+func setArrayElem(dest reflect.Value, text string) error {
+	var err error
+	switch dest.Kind() {
+	case reflect.String:
+		dest.SetString(text)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(text)
+		dest.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(text, 10, dest.Type().Bits())
+		dest.SetInt(n)
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		n, err = strconv.ParseUint(text, 10, dest.Type().Bits())
+		dest.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(text, dest.Type().Bits())
+		dest.SetFloat(f)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: array element %q to %s", constant.ErrConvert, text, dest.Type())
+	}
+	return nil
+}
+
+// ParseArray parses the text form of a one-dimensional Postgres array, like
+// {a,"b c",NULL}, into its elements; a NULL element is nil.
+func ParseArray(text string) ([]*string, error) {
+	// drop explicit bounds such as [0:2]={a,b,c}
+	if strings.HasPrefix(text, "[") {
+		if i := strings.Index(text, "="); i >= 0 {
+			text = text[i+1:]
+		}
+	}
+	if len(text) < 2 || text[0] != '{' || text[len(text)-1] != '}' {
+		return nil, fmt.Errorf("%w: malformed array %q", constant.ErrConvert, text)
+	}
+
+	body := text[1 : len(text)-1]
+	if strings.TrimSpace(body) == "" {
+		return []*string{}, nil
+	}
+
+	var elems []*string
+	for i := 0; ; {
+		for i < len(body) && body[i] == ' ' {
+			i++
+		}
+
+		var (
+			elem   strings.Builder
+			quoted bool
+		)
+		switch {
+		case i < len(body) && body[i] == '{':
+			return nil, fmt.Errorf("%w: multidimensional array %q", constant.ErrConvert, text)
+		case i < len(body) && body[i] == '"':
+			quoted = true
+			for i++; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' && i+1 < len(body) {
+					i++
+				}
+				elem.WriteByte(body[i])
+			}
+			if i >= len(body) {
+				return nil, fmt.Errorf("%w: unterminated array element in %q", constant.ErrConvert, text)
+			}
+			i++ // closing quote
+		default:
+			for ; i < len(body) && body[i] != ','; i++ {
+				if body[i] == '\\' && i+1 < len(body) {
+					i++
+				}
+				elem.WriteByte(body[i])
+			}
+		}
+
+		s := elem.String()
+		if !quoted {
+			s = strings.TrimSpace(s)
+		}
+		if !quoted && strings.EqualFold(s, "NULL") {
+			elems = append(elems, nil)
+		} else {
+			elems = append(elems, &s)
+		}
+
+		for i < len(body) && body[i] == ' ' {
+			i++
+		}
+		if i >= len(body) {
+			return elems, nil
+		}
+		if body[i] != ',' {
+			return nil, fmt.Errorf("%w: malformed array %q", constant.ErrConvert, text)
+		}
+		i++
+	}
+}
+
+// FormatArray returns the Postgres array text of slice v, quoting every
+// string element, e.g. []string{"a", "b c"} => {"a","b c"}.
+func FormatArray(v reflect.Value) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		e := v.Index(i)
+		switch e.Kind() {
+		case reflect.String:
+			b.WriteByte('"')
+			for _, r := range e.String() {
+				if r == '"' || r == '\\' {
+					b.WriteByte('\\')
+				}
+				b.WriteRune(r)
+			}
+			b.WriteByte('"')
+		case reflect.Bool:
+			b.WriteString(strconv.FormatBool(e.Bool()))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			b.WriteString(strconv.FormatInt(e.Int(), 10))
+		case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			b.WriteString(strconv.FormatUint(e.Uint(), 10))
+		case reflect.Float32, reflect.Float64:
+			// Postgres spells infinities out where Go prints +Inf and -Inf
+			switch f := e.Float(); {
+			case math.IsInf(f, 1):
+				b.WriteString("Infinity")
+			case math.IsInf(f, -1):
+				b.WriteString("-Infinity")
+			default:
+				b.WriteString(strconv.FormatFloat(f, 'g', -1, e.Type().Bits()))
+			}
+		}
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// BindArrays returns args with every slice mapped to a Postgres array bound
+// as its array text, and nil slices as NULL. args is returned as is when
+// it holds no such slice.
+func BindArrays(args []any) []any {
+	var out []any
+	for i, arg := range args {
+		v := reflect.ValueOf(arg)
+		if !v.IsValid() || !isArrayType(v.Type()) {
+			continue
+		}
+		if out == nil {
+			out = append([]any{}, args...)
+		}
+		if v.IsNil() {
+			out[i] = nil
+		} else {
+			out[i] = FormatArray(v)
+		}
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
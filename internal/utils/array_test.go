@@ -0,0 +1,118 @@
+package utils_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+	"github.com/i-sub135/i-sub-orm/internal/utils"
+)
+
+func TestParseArray(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []*string
+		wantErr bool
+	}{
+		{name: "empty", input: "{}", want: []*string{}},
+		{name: "plain", input: "{go,sql}", want: []*string{str("go"), str("sql")}},
+		{name: "quoted", input: `{"a b","c,d","say \"hi\"","back\\slash"}`, want: []*string{str("a b"), str("c,d"), str(`say "hi"`), str(`back\slash`)}},
+		{name: "nulls", input: `{1,NULL,"NULL"}`, want: []*string{str("1"), nil, str("NULL")}},
+		{name: "bounds", input: "[0:1]={7,8}", want: []*string{str("7"), str("8")}},
+		{name: "multidimensional", input: "{{1,2},{3,4}}", wantErr: true},
+		{name: "malformed", input: "1,2", wantErr: true},
+		{name: "unterminated", input: `{"abc}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := utils.ParseArray(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, constant.ErrConvert) {
+					t.Errorf("expected ErrConvert, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseArray failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseArray() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindArrays(t *testing.T) {
+	var nilTags []string
+	args := []any{[]string{"go", `a "b"`}, []int64{1, -2}, []bool{true}, nilTags, []byte("raw"), 3,
+		[]float64{1.5, math.Inf(1), math.Inf(-1), math.NaN()}}
+
+	got := utils.BindArrays(args)
+	want := []any{`{"go","a \"b\""}`, "{1,-2}", "{true}", nil, []byte("raw"), 3,
+		"{1.5,Infinity,-Infinity,NaN}"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BindArrays() = %v, want %v", got, want)
+	}
+
+	// a formatted array parses back to the same elements
+	elems, err := utils.ParseArray(got[0].(string))
+	if err != nil || len(elems) != 2 || *elems[1] != `a "b"` {
+		t.Errorf("round trip mismatch: %v, %v", elems, err)
+	}
+}
+
+type TaggedPost struct {
+	ID     int       `db:"id"`
+	Tags   []string  `db:"tags"`
+	Scores []int64   `db:"scores"`
+	Ratios []float64 `db:"ratios"`
+}
+
+func TestScanner_Arrays(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "tags", "scores", "ratios"}).
+		AddRow(1, []byte(`{go,"hello world"}`), []byte("{1,NULL,3}"), "{0.5}").
+		AddRow(2, nil, []byte("{}"), nil))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"scores"}).AddRow([]byte("{1,x}")))
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows.Close()
+
+	var posts []TaggedPost
+	if err := (utils.Scanner{Arrays: true}).Scan(rows, &posts); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	want := []TaggedPost{
+		{ID: 1, Tags: []string{"go", "hello world"}, Scores: []int64{1, 0, 3}, Ratios: []float64{0.5}},
+		{ID: 2, Scores: []int64{}},
+	}
+	if !reflect.DeepEqual(posts, want) {
+		t.Errorf("posts = %+v, want %+v", posts, want)
+	}
+
+	rows, err = db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows.Close()
+
+	var post TaggedPost
+	if err := (utils.Scanner{Arrays: true}).Scan(rows, &post); !errors.Is(err, constant.ErrConvert) {
+		t.Errorf("expected ErrConvert for a bad element, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"database/sql/driver"
+	"reflect"
+)
+
+// Converter converts a Go type from and to database values. Scan receives
+// the value returned by the driver, nil for NULL; a []byte is only valid
+// until the call returns. Value returns the value bound for an argument.
+// Either func may be nil to convert in one direction only.
+type Converter struct {
+	Scan  func(src any) (any, error)
+	Value func(v any) (driver.Value, error)
+}
+
+// Converters is a registry of converters by Go type. It is never modified in
+// place, so it can be shared by copies of a Scanner.
+type Converters map[reflect.Type]Converter
+
+// With returns a copy of c with conv registered for t
+func (c Converters) With(t reflect.Type, conv Converter) Converters {
+	out := make(Converters, len(c)+1)
+	for k, v := range c {
+		out[k] = v
+	}
+	out[t] = conv
+	return out
+}
+
+// target returns the convertTarget, without dest, for a destination of type
+// t: through the converter of t, or of T when t is a *T. Its scan func is
+// nil when there is none.
+func (c Converters) target(t reflect.Type) convertTarget {
+	if conv, ok := c[t]; ok && conv.Scan != nil {
+		return convertTarget{scan: conv.Scan}
+	}
+	if t.Kind() == reflect.Pointer {
+		if conv, ok := c[t.Elem()]; ok && conv.Scan != nil {
+			return convertTarget{scan: conv.Scan, elem: true}
+		}
+	}
+	return convertTarget{}
+}
+
+// Bind returns args with every value of a registered type, or a pointer to
+// one, replaced by its converted value. args is returned as is when nothing
+// needs converting.
+func (c Converters) Bind(args []any) ([]any, error) {
+	if len(c) == 0 {
+		return args, nil
+	}
+
+	var out []any
+	for i, arg := range args {
+		v, ok, err := c.value(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = append([]any{}, args...)
+		}
+		out[i] = v
+	}
+	if out == nil {
+		return args, nil
+	}
+	return out, nil
+}
+
+func (c Converters) value(arg any) (driver.Value, bool, error) {
+	if arg == nil {
+		return nil, false, nil
+	}
+	v := reflect.ValueOf(arg)
+	if conv, ok := c[v.Type()]; ok && conv.Value != nil {
+		out, err := conv.Value(arg)
+		return out, true, err
+	}
+	if v.Kind() == reflect.Pointer {
+		if conv, ok := c[v.Type().Elem()]; ok && conv.Value != nil {
+			if v.IsNil() {
+				return nil, true, nil
+			}
+			out, err := conv.Value(v.Elem().Interface())
+			return out, true, err
+		}
+	}
+	return nil, false, nil
+}
+
+// convertTarget is a sql.Scanner that sets dest through a converter; elem
+// means dest is a *T and the converter returns a T.
+type convertTarget struct {
+	dest reflect.Value
+	scan func(any) (any, error)
+	elem bool
+}
+
+// Scan implements sql.Scanner. A NULL into a *T destination leaves it nil
+// without calling the converter.
+func (c convertTarget) Scan(src any) error {
+	dest := c.dest
+	if c.elem {
+		if src == nil {
+			dest.SetZero()
+			return nil
+		}
+		if dest.IsNil() {
+			dest.Set(reflect.New(dest.Type().Elem()))
+		}
+		dest = dest.Elem()
+	}
+
+	v, err := c.scan(src)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		dest.SetZero()
+		return nil
+	}
+	dest.Set(reflect.ValueOf(v))
+	return nil
+}
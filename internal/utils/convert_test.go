@@ -0,0 +1,40 @@
+package utils_test
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/i-sub135/i-sub-orm/internal/utils"
+)
+
+type code string
+
+func TestConverters_Bind(t *testing.T) {
+	var convs utils.Converters
+	args := []any{code("a"), 1}
+
+	if got, err := convs.Bind(args); err != nil || &got[0] != &args[0] {
+		t.Errorf("expected args unchanged without converters, got %v, %v", got, err)
+	}
+
+	convs = convs.With(reflect.TypeOf(code("")), utils.Converter{
+		Value: func(v any) (driver.Value, error) {
+			return strings.ToUpper(string(v.(code))), nil
+		},
+	})
+	c := code("b")
+	var nilCode *code
+	got, err := convs.Bind([]any{code("a"), &c, nilCode, 1, nil})
+	if err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+	want := []any{"A", "B", nil, 1, nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if args[0] != code("a") {
+		t.Errorf("expected args not to be modified, got %v", args)
+	}
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+)
+
+// jsonScan returns the scan func of a field of type t tagged "json": the
+// column text is unmarshaled into a new t, and NULL leaves the zero value.
+func jsonScan(t reflect.Type) func(src any) (any, error) {
+	return func(src any) (any, error) {
+		var data []byte
+		switch v := src.(type) {
+		case nil:
+			return nil, nil
+		case []byte:
+			data = v
+		case string:
+			data = []byte(v)
+		default:
+			return nil, fmt.Errorf("%w: %T %v to JSON %s", constant.ErrConvert, src, src, t)
+		}
+		if len(data) == 0 {
+			return nil, nil
+		}
+
+		out := reflect.New(t)
+		if err := json.Unmarshal(data, out.Interface()); err != nil {
+			return nil, err
+		}
+		return out.Elem().Interface(), nil
+	}
+}
+
+// JSONValue returns the value bound for a field tagged "json": its JSON text
+// as a string, which Postgres json/jsonb, MySQL JSON and SQLite text columns
+// all accept, or NULL for a nil pointer, map or slice.
+func JSONValue(v reflect.Value) driver.Valuer {
+	return jsonValue{v: v}
+}
+
+type jsonValue struct {
+	v reflect.Value
+}
+
+// Value implements driver.Valuer
+func (j jsonValue) Value() (driver.Value, error) {
+	switch j.v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		if j.v.IsNil() {
+			return nil, nil
+		}
+	}
+	data, err := json.Marshal(j.v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
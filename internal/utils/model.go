@@ -0,0 +1,245 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Field describes a struct field mapped to a database column.
+type Field struct {
+	Column   string
+	Index    []int // index path for FieldByIndex
+	PK       bool  // tagged "pk", or the column named "id" when no field is tagged
+	Auto     bool  // tagged "auto": the database generates the value when it is zero
+	Required bool  // tagged "required": strict scans fail when the column is missing
+	JSON     bool  // tagged "json": stored as JSON text and unmarshaled on scan
+	Nested   bool  // belongs to a nested struct mapped with a prefix tag; read only
+}
+
+// ParseTag splits a db tag like "id,pk,auto" into the column name and its options.
+func ParseTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return strings.TrimSpace(parts[0]), parts[1:]
+}
+
+// Model is the column mapping of a struct type, built once per type and
+// shared by scanning and write paths.
+type Model struct {
+	Fields   []Field
+	byColumn map[string]int
+}
+
+// Field returns the field mapped to column. Like SQL identifiers, columns
+// match case-insensitively when no field has the exact name.
+func (m *Model) Field(column string) (Field, bool) {
+	if i, ok := m.byColumn[column]; ok {
+		return m.Fields[i], true
+	}
+	for _, f := range m.Fields {
+		if strings.EqualFold(f.Column, column) {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Mapper builds and caches the Model of struct types, naming untagged
+// fields with its column func. It is safe for concurrent use.
+type Mapper struct {
+	column func(field string) string
+	models sync.Map // reflect.Type -> *Model
+}
+
+// NewMapper returns a Mapper naming untagged fields with column, or
+// SnakeCase when column is nil.
+func NewMapper(column func(field string) string) *Mapper {
+	if column == nil {
+		column = SnakeCase
+	}
+	return &Mapper{column: column}
+}
+
+var defaultMapper = NewMapper(nil)
+
+// ModelOf returns the Model of struct type t, building it with StructFields
+// on first use. A nil Mapper maps with SnakeCase.
+func (m *Mapper) ModelOf(t reflect.Type) *Model {
+	if m == nil {
+		m = defaultMapper
+	}
+	if model, ok := m.models.Load(t); ok {
+		return model.(*Model)
+	}
+
+	fields := StructFields(t, m.column)
+	model := &Model{Fields: fields, byColumn: make(map[string]int, len(fields))}
+	for i, f := range fields {
+		model.byColumn[f.Column] = i
+	}
+	actual, _ := m.models.LoadOrStore(t, model)
+	return actual.(*Model)
+}
+
+// ModelOf returns the Model of struct type t with the default SnakeCase
+// column naming.
+func ModelOf(t reflect.Type) *Model {
+	return defaultMapper.ModelOf(t)
+}
+
+// SnakeCase converts a Go field name to snake_case, keeping acronyms
+// together: CreatedAt -> created_at, UserID -> user_id, HTTPCode -> http_code.
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	b.Grow(len(name) + 4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// a word starts at an upper case letter after a lower case one or
+			// a digit, or at the last upper case letter of an acronym
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) && runes[i-1] != '_' ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// StructFields returns the exported fields of struct type t mapped to their
+// column names; untagged fields are named by column, or SnakeCase when it
+// is nil. Fields of untagged anonymous (embedded) structs, or
+// pointers to them, are flattened into the parent; as with Go field
+// promotion, a shallower field wins over an embedded one with the same
+// column. A struct field with a prefix tag maps the fields of that struct
+// under the prefix, e.g. `db:"author" prefix:"author_"` maps Author.Name to
+// author_name. Fields tagged `db:"-"` are left out.
+func StructFields(t reflect.Type, column func(field string) string) []Field {
+	if column == nil {
+		column = SnakeCase
+	}
+	w := fieldWalker{column: column, visiting: map[reflect.Type]bool{t: true}}
+	fields := w.walk(t, nil, "", false)
+
+	// keep the shallowest field for every column
+	seen := make(map[string]int, len(fields))
+	out := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		if i, ok := seen[f.Column]; ok {
+			if len(f.Index) < len(out[i].Index) {
+				out[i] = f
+			}
+			continue
+		}
+		seen[f.Column] = len(out)
+		out = append(out, f)
+	}
+
+	pkTagged := false
+	for _, f := range out {
+		pkTagged = pkTagged || f.PK && !f.Nested
+	}
+	if !pkTagged {
+		for i, f := range out {
+			// the column may be named by a custom strategy, e.g. "ID"
+			if !f.Nested && strings.EqualFold(f.Column, "id") {
+				out[i].PK = true
+				break
+			}
+		}
+	}
+	return out
+}
+
+// fieldWalker collects the fields of a struct type; visiting holds the struct
+// types on the current path so self-referencing prefix structs do not
+// recurse forever.
+type fieldWalker struct {
+	column   func(field string) string
+	visiting map[reflect.Type]bool
+}
+
+func (w fieldWalker) walk(t reflect.Type, parent []int, prefix string, nested bool) []Field {
+	fields := make([]Field, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := append(append([]int{}, parent...), i)
+
+		col, opts := ParseTag(f.Tag.Get("db"))
+		if col == "-" {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		sub, hasPrefix := f.Tag.Lookup("prefix")
+		isStruct := ft.Kind() == reflect.Struct && !isScalar(ft)
+		embedded := f.Anonymous && col == "" && isStruct
+		if (embedded || hasPrefix && isStruct) && !w.visiting[ft] {
+			// unexported fields can only be walked when embedded by value
+			if !f.IsExported() && (!embedded || f.Type.Kind() == reflect.Pointer) {
+				continue
+			}
+			w.visiting[ft] = true
+			if embedded {
+				fields = append(fields, w.walk(ft, index, prefix, nested)...)
+			} else {
+				fields = append(fields, w.walk(ft, index, prefix+strings.ToLower(sub), true)...)
+			}
+			delete(w.visiting, ft)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if col == "" {
+			col = w.column(f.Name)
+		} else {
+			col = strings.ToLower(col)
+		}
+
+		field := Field{Column: prefix + col, Index: index, Nested: nested}
+		for _, opt := range opts {
+			switch strings.TrimSpace(opt) {
+			case "pk":
+				field.PK = true
+			case "auto":
+				field.Auto = true
+			case "required":
+				field.Required = true
+			case "json":
+				field.JSON = true
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// FieldByIndex returns the field of struct v at index, allocating the nil
+// struct pointers on the way so the field can be set.
+func FieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// LookupField returns the field of struct v at index without allocating;
+// ok is false when a struct pointer on the way is nil.
+func LookupField(v reflect.Value, index []int) (reflect.Value, bool) {
+	f, err := v.FieldByIndexErr(index)
+	return f, err == nil
+}
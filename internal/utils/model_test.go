@@ -0,0 +1,84 @@
+package utils_test
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/i-sub135/i-sub-orm/internal/utils"
+)
+
+func TestModelOf_Cached(t *testing.T) {
+	tipe := reflect.TypeOf(benchUser{})
+
+	models := make([]*utils.Model, 8)
+	var wg sync.WaitGroup
+	for i := range models {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			models[i] = utils.ModelOf(tipe)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, m := range models {
+		if m != models[0] {
+			t.Fatal("expected every caller to share the cached model")
+		}
+	}
+
+	f, ok := models[0].Field("created_at")
+	if !ok || !reflect.DeepEqual(f.Index, []int{5}) {
+		t.Errorf("unexpected created_at field: %+v, %v", f, ok)
+	}
+	if f, ok := models[0].Field("CREATED_AT"); !ok || !reflect.DeepEqual(f.Index, []int{5}) {
+		t.Errorf("expected case-insensitive match for CREATED_AT, got %+v, %v", f, ok)
+	}
+	if _, ok := models[0].Field("missing"); ok {
+		t.Error("expected no field for unknown column")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"ID":         "id",
+		"Name":       "name",
+		"CreatedAt":  "created_at",
+		"UserID":     "user_id",
+		"HTTPCode":   "http_code",
+		"APIKeyHash": "api_key_hash",
+		"Address2":   "address2",
+		"Line2Text":  "line2_text",
+		"Snake_Case": "snake_case",
+	}
+	for in, want := range tests {
+		if got := utils.SnakeCase(in); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMapper_ColumnNaming(t *testing.T) {
+	type event struct {
+		EventID   int
+		CreatedAt string
+		Note      string `db:"remark"`
+	}
+	tipe := reflect.TypeOf(event{})
+
+	snake := utils.ModelOf(tipe)
+	for _, col := range []string{"event_id", "created_at", "remark"} {
+		if _, ok := snake.Field(col); !ok {
+			t.Errorf("expected column %q with default naming", col)
+		}
+	}
+
+	upper := utils.NewMapper(strings.ToUpper).ModelOf(tipe)
+	for _, col := range []string{"EVENTID", "CREATEDAT", "remark"} {
+		if _, ok := upper.Field(col); !ok {
+			t.Errorf("expected column %q with custom naming", col)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+)
+
+// ExpandNamed replaces :name parameters in query with ? placeholders and
+// returns the values looked up for them in order, ready for
+// RebindPlaceholder. Parameters inside string literals, quoted identifiers
+// and comments are left alone, as are Postgres :: casts.
+func ExpandNamed(query, driver string, lookup func(name string) (any, bool)) (string, []any, error) {
+	r := newRebinder(query, driver)
+	args := make([]any, 0)
+	var result strings.Builder
+	result.Grow(len(query))
+
+	for i := 0; i < len(query); {
+		if end, ok := r.skip(i); ok {
+			result.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		c := query[i]
+		if c == ':' && i+1 < len(query) && query[i+1] == ':' {
+			result.WriteString("::")
+			i += 2
+			continue
+		}
+		if c != ':' || i+1 >= len(query) || !isNameStart(query[i+1]) {
+			result.WriteByte(c)
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(query) && isIdentByte(query[end]) {
+			end++
+		}
+		name := query[i+1 : end]
+		v, ok := lookup(name)
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %s", constant.ErrNamedParam, name)
+		}
+		result.WriteByte('?')
+		args = append(args, v)
+		i = end
+	}
+
+	return result.String(), args, nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
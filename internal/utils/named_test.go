@@ -0,0 +1,82 @@
+package utils_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+	"github.com/i-sub135/i-sub-orm/internal/utils"
+)
+
+func TestExpandNamed(t *testing.T) {
+	values := map[string]any{"from": 1, "to": 2, "name": "john"}
+	lookup := func(name string) (any, bool) {
+		v, ok := values[name]
+		return v, ok
+	}
+
+	tests := []struct {
+		name     string
+		driver   string
+		query    string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "named parameters in order",
+			query:    "created_at BETWEEN :from AND :to",
+			wantSQL:  "created_at BETWEEN ? AND ?",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "repeated parameter",
+			query:    "a = :name OR b = :name",
+			wantSQL:  "a = ? OR b = ?",
+			wantArgs: []any{"john", "john"},
+		},
+		{
+			name:     "casts, literals and comments are kept",
+			query:    "a = :from::int AND b = ':to' /* :to */ AND c = :to",
+			wantSQL:  "a = ?::int AND b = ':to' /* :to */ AND c = ?",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "mysql double-quoted string with backslash escape",
+			driver:   "mysql",
+			query:    `a = "it\"s :to" AND b = :from`,
+			wantSQL:  `a = "it\"s :to" AND b = ?`,
+			wantArgs: []any{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver := tt.driver
+			if driver == "" {
+				driver = "postgres"
+			}
+			sql, args, err := utils.ExpandNamed(tt.query, driver, lookup)
+			if err != nil {
+				t.Fatalf("ExpandNamed failed: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("ExpandNamed() sql = %v, want %v", sql, tt.wantSQL)
+			}
+			if len(args) != len(tt.wantArgs) {
+				t.Fatalf("ExpandNamed() args = %v, want %v", args, tt.wantArgs)
+			}
+			for i := range args {
+				if args[i] != tt.wantArgs[i] {
+					t.Errorf("ExpandNamed() args = %v, want %v", args, tt.wantArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandNamed_Missing(t *testing.T) {
+	_, _, err := utils.ExpandNamed("a = :missing", "postgres", func(string) (any, bool) { return nil, false })
+	if !errors.Is(err, constant.ErrNamedParam) {
+		t.Errorf("expected ErrNamedParam, got %v", err)
+	}
+}
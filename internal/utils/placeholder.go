@@ -1,35 +1,205 @@
 package utils
 
 import (
-	"fmt"
+	"strconv"
 	"strings"
 )
 
 // RebindPlaceholder converts ? placeholders to the format used by the specified driver
 // For postgres: $1, $2, $3
+// For sqlserver: @p1, @p2, @p3
+// For oracle: :1, :2, :3
 // For mysql/sqlite: ? (no change)
+//
+// A ? inside a string literal, quoted identifier, comment or dollar-quoted
+// body is left alone. On Postgres, ?? is written as a literal ? (the JSONB
+// key operator) and the operators ?| and ?& are kept as is; other drivers
+// leave ?? as two placeholders. On MySQL "..." is a string literal, as in
+// its default SQL mode, so backslash escapes inside it are honored, as they
+// are in Postgres E'...' escape strings.
 func RebindPlaceholder(query string, driver string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	r := newRebinder(query, driver)
 	switch driver {
 	case "postgres", "postgresql":
-		return rebindPostgres(query)
+		r.bind = func(n int) string { return "$" + strconv.Itoa(n) }
+	case "sqlserver", "mssql":
+		r.bind = func(n int) string { return "@p" + strconv.Itoa(n) }
+	case "oracle", "godror":
+		r.bind = func(n int) string { return ":" + strconv.Itoa(n) }
 	default:
-		return query
+		r.bind = func(int) string { return "?" }
 	}
+	return r.rebind()
+}
+
+// newRebinder sets up the lexing rules of the driver's SQL dialect.
+func newRebinder(query, driver string) rebinder {
+	r := rebinder{query: query}
+	switch driver {
+	case "postgres", "postgresql":
+		r.postgres = true
+	case "sqlserver", "mssql":
+		r.brackets = true
+	case "mysql":
+		r.backslash = true
+	}
+	return r
+}
+
+// rebinder walks a query token by token so placeholders are only rewritten
+// where the SQL parser would see them.
+type rebinder struct {
+	query     string
+	bind      func(n int) string
+	postgres  bool // dollar-quoted bodies, E'...' strings, ?? escapes and ?|, ?& operators
+	brackets  bool // [quoted identifiers]
+	backslash bool // backslash escapes inside '...' and "..." string literals
 }
 
-// rebindPostgres converts ? to $1, $2, $3, etc.
-func rebindPostgres(query string) string {
+func (r rebinder) rebind() string {
+	q := r.query
 	count := 1
 	var result strings.Builder
+	result.Grow(len(q) + 8)
 
-	for i := 0; i < len(query); i++ {
-		if query[i] == '?' {
-			result.WriteString(fmt.Sprintf("$%d", count))
+	for i := 0; i < len(q); {
+		if end, ok := r.skip(i); ok {
+			result.WriteString(q[i:end])
+			i = end
+			continue
+		}
+
+		if q[i] != '?' {
+			result.WriteByte(q[i])
+			i++
+			continue
+		}
+
+		switch {
+		case r.postgres && strings.HasPrefix(q[i:], "??"):
+			result.WriteByte('?')
+			i += 2
+		case r.postgres && (strings.HasPrefix(q[i:], "?&") || strings.HasPrefix(q[i:], "?|") && !strings.HasPrefix(q[i:], "?||")):
+			result.WriteString(q[i : i+2])
+			i += 2
+		default:
+			result.WriteString(r.bind(count))
 			count++
-		} else {
-			result.WriteByte(query[i])
+			i++
 		}
 	}
 
 	return result.String()
 }
+
+// skip reports whether a string literal, quoted identifier, comment or
+// dollar-quoted body starts at q[i], and returns the index just past it.
+func (r rebinder) skip(i int) (int, bool) {
+	q := r.query
+	switch c := q[i]; {
+	case c == '\'':
+		return r.skipQuoted(i, '\'', r.backslash || r.postgres && isEscapeString(q, i)), true
+	case c == '"':
+		return r.skipQuoted(i, c, r.backslash), true
+	case c == '`':
+		return r.skipQuoted(i, c, false), true
+	case c == '[' && r.brackets:
+		return r.skipQuoted(i, ']', false), true
+	case c == '-' && strings.HasPrefix(q[i:], "--"):
+		if end := strings.IndexByte(q[i:], '\n'); end >= 0 {
+			return i + end, true
+		}
+		return len(q), true
+	case c == '/' && strings.HasPrefix(q[i:], "/*"):
+		return skipBlockComment(q, i), true
+	case c == '$' && r.postgres:
+		if end := skipDollarQuoted(q, i); end > i+1 {
+			return end, true
+		}
+	}
+	return i, false
+}
+
+// skipQuoted returns the index just past the quoted token starting at
+// q[start]. A doubled closing quote is an escaped quote; an unterminated
+// token runs to the end of the query.
+func (r rebinder) skipQuoted(start int, closing byte, backslash bool) int {
+	q := r.query
+	for i := start + 1; i < len(q); i++ {
+		switch {
+		case backslash && q[i] == '\\':
+			i++
+		case q[i] == closing:
+			if i+1 < len(q) && q[i+1] == closing {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(q)
+}
+
+// skipBlockComment returns the index just past the (possibly nested)
+// /* ... */ comment starting at q[start].
+func skipBlockComment(q string, start int) int {
+	depth := 0
+	for i := start; i < len(q)-1; i++ {
+		switch {
+		case q[i] == '/' && q[i+1] == '*':
+			depth++
+			i++
+		case q[i] == '*' && q[i+1] == '/':
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(q)
+}
+
+// skipDollarQuoted returns the index just past the Postgres dollar-quoted
+// body ($$...$$ or $tag$...$tag$) starting at q[start], or start+1 when the
+// $ does not open one (e.g. an existing $1 placeholder).
+func skipDollarQuoted(q string, start int) int {
+	if start > 0 && isIdentByte(q[start-1]) {
+		return start + 1
+	}
+
+	end := start + 1
+	for end < len(q) && isIdentByte(q[end]) {
+		if end == start+1 && q[end] >= '0' && q[end] <= '9' {
+			return start + 1
+		}
+		end++
+	}
+	if end >= len(q) || q[end] != '$' {
+		return start + 1
+	}
+
+	tag := q[start : end+1]
+	if close := strings.Index(q[end+1:], tag); close >= 0 {
+		return end + 1 + close + len(tag)
+	}
+	return len(q)
+}
+
+// isEscapeString reports whether the quote at q[start] opens a Postgres
+// E'...' escape string, whose backslash escapes are honored. An e that ends
+// a longer identifier, as in name'...', does not open one.
+func isEscapeString(q string, start int) bool {
+	if start == 0 || q[start-1] != 'E' && q[start-1] != 'e' {
+		return false
+	}
+	return start == 1 || !isIdentByte(q[start-2])
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,127 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/i-sub135/i-sub-orm/internal/utils"
+)
+
+func TestRebindPlaceholder(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		driver string
+		want   string
+	}{
+		{
+			name:   "postgres placeholders",
+			query:  "SELECT * FROM users WHERE id = ? AND name = ?",
+			driver: "postgres",
+			want:   "SELECT * FROM users WHERE id = $1 AND name = $2",
+		},
+		{
+			name:   "sqlserver placeholders",
+			query:  "SELECT * FROM users WHERE id = ? AND name = ?",
+			driver: "sqlserver",
+			want:   "SELECT * FROM users WHERE id = @p1 AND name = @p2",
+		},
+		{
+			name:   "oracle placeholders",
+			query:  "SELECT * FROM users WHERE id = ? AND name = ?",
+			driver: "oracle",
+			want:   "SELECT * FROM users WHERE id = :1 AND name = :2",
+		},
+		{
+			name:   "mysql unchanged",
+			query:  "SELECT * FROM users WHERE id = ?",
+			driver: "mysql",
+			want:   "SELECT * FROM users WHERE id = ?",
+		},
+		{
+			name:   "string literal with escaped quote",
+			query:  "SELECT 'what?', 'it''s ?' FROM t WHERE a = ?",
+			driver: "postgres",
+			want:   "SELECT 'what?', 'it''s ?' FROM t WHERE a = $1",
+		},
+		{
+			name:   "postgres escape string with backslash escape",
+			query:  `SELECT E'it\'s ?', e'\\' FROM t WHERE a = ? AND b = ?`,
+			driver: "postgres",
+			want:   `SELECT E'it\'s ?', e'\\' FROM t WHERE a = $1 AND b = $2`,
+		},
+		{
+			name:   "postgres identifier ending in e before a standard string",
+			query:  `SELECT name'\' FROM t WHERE a = ?`,
+			driver: "postgres",
+			want:   `SELECT name'\' FROM t WHERE a = $1`,
+		},
+		{
+			name:   "quoted identifiers",
+			query:  `SELECT "col?", ` + "`x?`" + ` FROM t WHERE a = ?`,
+			driver: "postgres",
+			want:   `SELECT "col?", ` + "`x?`" + ` FROM t WHERE a = $1`,
+		},
+		{
+			name:   "oracle double-quoted identifier without backslash escape",
+			query:  `SELECT "a\" FROM t WHERE a = ? AND b = "?"`,
+			driver: "oracle",
+			want:   `SELECT "a\" FROM t WHERE a = :1 AND b = "?"`,
+		},
+		{
+			name:   "mysql keeps ??",
+			query:  "SELECT * FROM t WHERE a = ?? ",
+			driver: "mysql",
+			want:   "SELECT * FROM t WHERE a = ?? ",
+		},
+		{
+			name:   "sqlserver binds ?? as two placeholders",
+			query:  "SELECT ?? FROM t",
+			driver: "sqlserver",
+			want:   "SELECT @p1@p2 FROM t",
+		},
+		{
+			name:   "comments",
+			query:  "SELECT a -- why?\nFROM t /* really? /* nested? */ */ WHERE a = ?",
+			driver: "postgres",
+			want:   "SELECT a -- why?\nFROM t /* really? /* nested? */ */ WHERE a = $1",
+		},
+		{
+			name:   "dollar-quoted body",
+			query:  "SELECT $$a?b$$, $fn$c?$fn$ WHERE a = ?",
+			driver: "postgres",
+			want:   "SELECT $$a?b$$, $fn$c?$fn$ WHERE a = $1",
+		},
+		{
+			name:   "jsonb operators",
+			query:  "SELECT * FROM t WHERE data ?? 'k' AND data ?| ? AND data ?& ? AND a = ?::int",
+			driver: "postgres",
+			want:   "SELECT * FROM t WHERE data ? 'k' AND data ?| $1 AND data ?& $2 AND a = $3::int",
+		},
+		{
+			name:   "concat after placeholder",
+			query:  "SELECT ?||'x'",
+			driver: "postgres",
+			want:   "SELECT $1||'x'",
+		},
+		{
+			name:   "sqlserver bracket identifier",
+			query:  "SELECT [a?]]b] FROM t WHERE a = ?",
+			driver: "sqlserver",
+			want:   "SELECT [a?]]b] FROM t WHERE a = @p1",
+		},
+		{
+			name:   "mysql backslash escape",
+			query:  `SELECT 'a\'?' WHERE a = ?`,
+			driver: "mysql",
+			want:   `SELECT 'a\'?' WHERE a = ?`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := utils.RebindPlaceholder(tt.query, tt.driver); got != tt.want {
+				t.Errorf("RebindPlaceholder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
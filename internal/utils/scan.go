@@ -2,13 +2,58 @@ package utils
 
 import (
 	"database/sql"
+	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/i-sub135/i-sub-orm/internal/constant"
 )
 
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// Scanner scans query results into Go values. The zero value is the lenient
+// scanner used by ScanRows.
+type Scanner struct {
+	// Mapper maps struct fields to columns; nil uses the SnakeCase default.
+	Mapper *Mapper
+
+	// Strict makes struct scans fail with constant.ErrStrictScan when a
+	// column has no matching field, or a field tagged "required" has no
+	// column in the result, instead of silently skipping them.
+	Strict bool
+
+	// ZeroOnNull scans NULL into plain (non-pointer, non-Scanner) fields
+	// and scalar destinations as their zero value instead of failing.
+	ZeroOnNull bool
+
+	// Converters scan the values of their types, taking precedence over
+	// sql.Scanner and ZeroOnNull.
+	Converters Converters
+
+	// Arrays scans Postgres array columns into slice fields of strings,
+	// bools, integers and floats.
+	Arrays bool
+}
+
+// ScanRows scans rows into dest with the lenient Scanner. dest must be a
+// pointer to one of:
+//   - a struct, a scalar (int, string, time.Time, sql.Scanner, ...) or a
+//     map[string]any, filled from the first row (sql.ErrNoRows when empty)
+//   - a slice of structs, struct pointers, scalars or map[string]any,
+//     with one element per row
+//
+// Scalar destinations require a single-column result.
 func ScanRows(rows *sql.Rows, dest any) error {
+	return Scanner{}.Scan(rows, dest)
+}
+
+// Scan scans rows into dest, see ScanRows.
+func (s Scanner) Scan(rows *sql.Rows, dest any) error {
 	destVal := reflect.ValueOf(dest)
 
 	if destVal.Kind() != reflect.Pointer {
@@ -22,24 +67,43 @@ func ScanRows(rows *sql.Rows, dest any) error {
 		return err
 	}
 
-	switch destVal.Kind() {
+	switch {
 
-	//destination == slice of struct
-	case reflect.Slice:
+	//destination == slice of struct, struct pointer, scalar or map
+	case destVal.Kind() == reflect.Slice && !s.scalar(destVal.Type()):
 		elemType := destVal.Type().Elem()
+		isPtr := elemType.Kind() == reflect.Pointer
+		if isPtr {
+			elemType = elemType.Elem()
+		}
+		plan, err := s.plan(elemType, cols)
+		if err != nil {
+			return err
+		}
 		for rows.Next() {
 			elemPtr := reflect.New(elemType)
-			if err := intoStruct(rows, elemPtr.Elem(), cols); err != nil {
+			if err := intoValue(rows, elemPtr.Elem(), plan); err != nil {
 				return err
 			}
-			destVal.Set(reflect.Append(destVal, elemPtr.Elem()))
+			if isPtr {
+				destVal.Set(reflect.Append(destVal, elemPtr))
+			} else {
+				destVal.Set(reflect.Append(destVal, elemPtr.Elem()))
+			}
 		}
 		return rows.Err()
 
-	//destination == single struct
-	case reflect.Struct:
+	//destination == single struct, scalar or map
+	case destVal.Kind() == reflect.Struct || destVal.Kind() == reflect.Map || s.scalar(destVal.Type()):
+		plan, err := s.plan(destVal.Type(), cols)
+		if err != nil {
+			return err
+		}
 		if rows.Next() {
-			return intoStruct(rows, destVal, cols)
+			return intoValue(rows, destVal, plan)
+		}
+		if err := rows.Err(); err != nil {
+			return err
 		}
 		return sql.ErrNoRows
 	default:
@@ -47,36 +111,293 @@ func ScanRows(rows *sql.Rows, dest any) error {
 	}
 }
 
-func intoStruct(rows *sql.Rows, dest reflect.Value, cols []string) error {
+// isScalar reports whether t is scanned from a single column as a whole:
+// basic kinds, []byte, time.Time, any and sql.Scanner implementations.
+func isScalar(t reflect.Type) bool {
+	if t == timeType || reflect.PointerTo(t).Implements(scannerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Interface,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	default:
+		return false
+	}
+}
+
+// scalar reports whether t is scanned from a single column as a whole: a
+// scalar type or one with a registered converter.
+func (s Scanner) scalar(t reflect.Type) bool {
+	return isScalar(t) || s.Converters.target(t).scan != nil
+}
 
-	fieldMap := make(map[string]reflect.Value)
-	tipe := dest.Type()
+// scanPlan maps the columns of a result set onto a destination type once,
+// so rows are scanned without looking fields up again.
+type scanPlan struct {
+	cols    []string
+	indexes [][]int         // field index path per column; nil when the column is skipped
+	zero    []bool          // per column: NULL scans as the zero value of the field
+	conv    []convertTarget // per column: registered converter, if any
+	scalar  bool            // the destination is scanned from a single column
+	nested  [][]int         // per column: the groups of the struct pointers on its field path
+	groups  [][]int         // field index paths of struct pointers holding columns
+}
 
-	for i := 0; i < dest.NumField(); i++ {
-		f := tipe.Field(i)
-		colName := strings.ToLower(f.Name)
-		if tag := f.Tag.Get("db"); tag != "" {
-			colName = strings.ToLower(tag)
+func (s Scanner) plan(t reflect.Type, cols []string) (*scanPlan, error) {
+	plan := &scanPlan{cols: cols, zero: make([]bool, len(cols)), conv: make([]convertTarget, len(cols))}
+	if s.scalar(t) {
+		plan.scalar = true
+		if len(cols) == 1 {
+			plan.conv[0] = s.Converters.target(t)
+			plan.zero[0] = s.ZeroOnNull && !nullable(t)
 		}
-		fieldMap[colName] = dest.Field(i)
+		return plan, nil
+	}
+	if t.Kind() != reflect.Struct {
+		return plan, nil
 	}
 
-	values := make([]any, len(cols))
-	valuesPtr := make([]any, len(cols))
-
+	model := s.Mapper.ModelOf(t)
+	plan.indexes = make([][]int, len(cols))
+	plan.nested = make([][]int, len(cols))
+	var unmatched []string
+	matched := make(map[string]bool, len(cols)) // field columns, which may differ in case from cols
 	for i, col := range cols {
-		if f, ok := fieldMap[col]; ok && f.CanSet() {
-			valuesPtr[i] = f.Addr().Interface()
+		if f, ok := model.Field(col); ok {
+			matched[f.Column] = true
+			ft := t.FieldByIndex(f.Index).Type
+			plan.indexes[i] = f.Index
+			plan.conv[i] = s.Converters.target(ft)
+			switch {
+			case f.JSON:
+				plan.conv[i] = convertTarget{scan: jsonScan(ft)}
+			case s.Arrays && plan.conv[i].scan == nil && isArrayType(ft):
+				plan.conv[i] = convertTarget{scan: arrayScan(ft)}
+			}
+			plan.zero[i] = s.ZeroOnNull && !nullable(ft)
+			plan.nest(t, i, f.Index)
 		} else {
-			var skip any
-			valuesPtr[i] = &skip
+			unmatched = append(unmatched, col)
+		}
+	}
+	if !s.Strict {
+		return plan, nil
+	}
+
+	var missing []string
+	for _, f := range model.Fields {
+		if f.Required && !matched[f.Column] {
+			missing = append(missing, f.Column)
+		}
+	}
+	if len(unmatched) == 0 && len(missing) == 0 {
+		return plan, nil
+	}
+
+	var details []string
+	if len(unmatched) > 0 {
+		details = append(details, fmt.Sprintf("unmatched columns %v", unmatched))
+	}
+	if len(missing) > 0 {
+		details = append(details, fmt.Sprintf("missing required columns %v", missing))
+	}
+	return nil, fmt.Errorf("%w for %s: %s", constant.ErrStrictScan, t, strings.Join(details, ", "))
+}
+
+// nest records the struct pointers crossed by the field path of column i,
+// so they are only allocated when one of their columns is not NULL.
+func (p *scanPlan) nest(t reflect.Type, i int, index []int) {
+	for k := 1; k < len(index); k++ {
+		if t.FieldByIndex(index[:k]).Type.Kind() != reflect.Pointer {
+			continue
+		}
+		g := slices.IndexFunc(p.groups, func(path []int) bool { return slices.Equal(path, index[:k]) })
+		if g < 0 {
+			g = len(p.groups)
+			p.groups = append(p.groups, index[:k])
+		}
+		p.nested[i] = append(p.nested[i], g)
+	}
+}
+
+// intoValue scans the current row into dest according to its type
+func intoValue(rows *sql.Rows, dest reflect.Value, plan *scanPlan) error {
+	switch {
+	case plan.scalar:
+		if len(plan.cols) != 1 {
+			return constant.ErrScalarColumns
+		}
+		if target := plan.conv[0]; target.scan != nil {
+			target.dest = dest
+			return rows.Scan(target)
+		}
+		if !plan.zero[0] {
+			return rows.Scan(dest.Addr().Interface())
+		}
+		target := nullTarget(dest.Type())
+		if err := rows.Scan(target); err != nil {
+			return err
+		}
+		assignNull(dest, target)
+		return nil
+	case dest.Kind() == reflect.Struct:
+		return intoStruct(rows, dest, plan)
+	case dest.Kind() == reflect.Map:
+		return intoMap(rows, dest, plan.cols)
+	default:
+		return constant.ErrDestinationType
+	}
+}
+
+// intoStruct scans the current row into the fields of dest. Columns under a
+// struct pointer are scanned through a NULL check first, and the pointer is
+// left nil when all of them are NULL, e.g. for a LEFT JOIN without a match.
+func intoStruct(rows *sql.Rows, dest reflect.Value, plan *scanPlan) error {
+
+	values := make([]any, len(plan.cols))
+	var null []bool
+	if len(plan.groups) > 0 {
+		null = make([]bool, len(plan.cols))
+	}
+
+	for i, index := range plan.indexes {
+		switch {
+		case index == nil:
+			values[i] = new(any)
+		case plan.conv[i].scan != nil:
+			target := plan.conv[i]
+			target.dest = FieldByIndex(dest, index)
+			values[i] = target
+			if plan.nested[i] != nil {
+				values[i] = nullCheck{target: target, null: &null[i]}
+			}
+		case plan.zero[i] || plan.nested[i] != nil:
+			values[i] = nullTarget(dest.Type().FieldByIndex(index).Type)
+		default:
+			values[i] = FieldByIndex(dest, index).Addr().Interface()
 		}
-		values[i] = valuesPtr[i]
 	}
 
 	if err := rows.Scan(values...); err != nil {
 		return err
 	}
+	if len(plan.groups) == 0 {
+		for i, index := range plan.indexes {
+			if index != nil && plan.zero[i] && plan.conv[i].scan == nil {
+				assignNull(FieldByIndex(dest, index), values[i])
+			}
+		}
+		return nil
+	}
+
+	// a struct pointer is kept once any of its columns holds a value
+	used := make([]bool, len(plan.groups))
+	for i, index := range plan.indexes {
+		if index == nil || plan.nested[i] == nil {
+			continue
+		}
+		if plan.conv[i].scan == nil {
+			null[i] = reflect.ValueOf(values[i]).Elem().IsNil()
+		}
+		if !null[i] {
+			for _, g := range plan.nested[i] {
+				used[g] = true
+			}
+		}
+	}
+	for g, index := range plan.groups {
+		if f, ok := LookupField(dest, index); ok && !used[g] {
+			f.SetZero()
+		}
+	}
+
+	for i, index := range plan.indexes {
+		if index == nil || plan.conv[i].scan != nil || (!plan.zero[i] && plan.nested[i] == nil) {
+			continue
+		}
+		if slices.ContainsFunc(plan.nested[i], func(g int) bool { return !used[g] }) {
+			continue // the field lies under a struct pointer left nil
+		}
+		if ft := dest.Type().FieldByIndex(index).Type; null[i] && !plan.zero[i] && !nullable(ft) {
+			return fmt.Errorf("%w: NULL to %s for column %q", constant.ErrConvert, ft, plan.cols[i])
+		}
+		assignNull(FieldByIndex(dest, index), values[i])
+	}
+	return nil
+}
+
+// nullCheck scans through a converter target and records whether the column
+// was NULL.
+type nullCheck struct {
+	target convertTarget
+	null   *bool
+}
+
+// Scan implements sql.Scanner.
+func (n nullCheck) Scan(src any) error {
+	*n.null = src == nil
+	return n.target.Scan(src)
+}
+
+// nullTarget returns a **T to scan a column of type T through, so NULL
+// leaves a nil *T instead of failing the conversion.
+func nullTarget(t reflect.Type) any {
+	return reflect.New(reflect.PointerTo(t)).Interface()
+}
+
+// assignNull sets dest from a target returned by nullTarget, or to its zero
+// value when the column was NULL.
+func assignNull(dest reflect.Value, target any) {
+	if v := reflect.ValueOf(target).Elem(); v.IsNil() {
+		dest.SetZero()
+	} else {
+		dest.Set(v.Elem())
+	}
+}
+
+// nullable reports whether a NULL can be scanned into t as is: pointers,
+// interfaces, []byte and sql.Scanner implementations.
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	}
+	return reflect.PointerTo(t).Implements(scannerType)
+}
+
+// intoMap scans the current row into a map[string]any keyed by column name.
+// Text returned by the driver as []byte is stored as string.
+func intoMap(rows *sql.Rows, dest reflect.Value, cols []string) error {
+	if dest.Type().Key().Kind() != reflect.String || dest.Type().Elem().Kind() != reflect.Interface {
+		return constant.ErrDestinationType
+	}
+	if dest.IsNil() {
+		dest.Set(reflect.MakeMapWithSize(dest.Type(), len(cols)))
+	}
+
+	values := make([]any, len(cols))
+	valuesPtr := make([]any, len(cols))
+	for i := range values {
+		valuesPtr[i] = &values[i]
+	}
+
+	if err := rows.Scan(valuesPtr...); err != nil {
+		return err
+	}
 
+	for i, col := range cols {
+		v := values[i]
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+		dest.SetMapIndex(reflect.ValueOf(col), reflect.ValueOf(&v).Elem())
+	}
 	return nil
 }
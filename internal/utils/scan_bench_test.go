@@ -0,0 +1,136 @@
+package utils_test
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/i-sub135/i-sub-orm/internal/utils"
+)
+
+type benchUser struct {
+	ID        int64     `db:"id"`
+	Email     string    `db:"email"`
+	Name      string    `db:"name"`
+	Age       int       `db:"age"`
+	Active    bool      `db:"active"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func BenchmarkScanRows(b *testing.B) {
+	benchmarkScan(b, func(rows *sql.Rows, users *[]benchUser) error {
+		return utils.ScanRows(rows, users)
+	})
+}
+
+// BenchmarkScanRowsBaseline runs the same scan with the ScanRows loop this
+// package had before struct metadata was cached, which rebuilt the field
+// map of every row, as the baseline for BenchmarkScanRows.
+func BenchmarkScanRowsBaseline(b *testing.B) {
+	benchmarkScan(b, baselineScanRows)
+}
+
+// benchmarkScan times scan over a fresh result of 10000 rows per iteration
+func benchmarkScan(b *testing.B, scan func(rows *sql.Rows, users *[]benchUser) error) {
+	const n = 10000
+	now := time.Now()
+	cols := []string{"id", "email", "name", "age", "active", "created_at", "updated_at"}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		b.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		rows := sqlmock.NewRows(cols)
+		for j := 0; j < n; j++ {
+			rows.AddRow(int64(j), "john@example.com", "John Doe", 30, true, now, now)
+		}
+		mock.ExpectQuery("SELECT").WillReturnRows(rows)
+		queryRows, err := db.Query("SELECT")
+		if err != nil {
+			b.Fatalf("failed to query: %v", err)
+		}
+		users := make([]benchUser, 0, n)
+		b.StartTimer()
+
+		if err := scan(queryRows, &users); err != nil {
+			b.Fatalf("scan failed: %v", err)
+		}
+		queryRows.Close()
+	}
+}
+
+// baselineScanRows is the slice case of the former ScanRows: the field map
+// of the struct is built again for every row.
+func baselineScanRows(rows *sql.Rows, users *[]benchUser) error {
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var u benchUser
+		dest := reflect.ValueOf(&u).Elem()
+
+		fieldMap := make(map[string]reflect.Value)
+		tipe := dest.Type()
+		for i := 0; i < dest.NumField(); i++ {
+			f := tipe.Field(i)
+			colName := strings.ToLower(f.Name)
+			if tag := f.Tag.Get("db"); tag != "" {
+				colName = strings.ToLower(tag)
+			}
+			fieldMap[colName] = dest.Field(i)
+		}
+
+		values := make([]any, len(cols))
+		for i, col := range cols {
+			if f, ok := fieldMap[col]; ok && f.CanSet() {
+				values[i] = f.Addr().Interface()
+			} else {
+				var skip any
+				values[i] = &skip
+			}
+		}
+		if err := rows.Scan(values...); err != nil {
+			return err
+		}
+		*users = append(*users, u)
+	}
+	return rows.Err()
+}
+
+func BenchmarkStructFields(b *testing.B) {
+	t := reflect.TypeOf(benchUser{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		utils.StructFields(t, nil)
+	}
+}
+
+// BenchmarkModelOf compares building the column mapping of a struct on
+// every lookup with reading it from the Mapper cache.
+func BenchmarkModelOf(b *testing.B) {
+	t := reflect.TypeOf(benchUser{})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			utils.NewMapper(nil).ModelOf(t)
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		m := utils.NewMapper(nil)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m.ModelOf(t)
+		}
+	})
+}
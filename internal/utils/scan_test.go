@@ -2,7 +2,11 @@ package utils_test
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/i-sub135/i-sub-orm/internal/constant"
@@ -226,8 +230,8 @@ func TestScanRows_ErrorInvalidType(t *testing.T) {
 	}
 	defer queryRows.Close()
 
-	var invalidDest string
-	err = utils.ScanRows(queryRows, &invalidDest) // Invalid type (not slice or struct)
+	var invalidDest chan int
+	err = utils.ScanRows(queryRows, &invalidDest) // Invalid type (not struct, scalar, map or slice)
 	if err != constant.ErrDestinationType {
 		t.Errorf("expected ErrDestinationType, got %v", err)
 	}
@@ -298,3 +302,473 @@ func TestScanRows_ExtraColumns(t *testing.T) {
 		t.Errorf("user data mismatch: %+v", user)
 	}
 }
+
+func TestScanRows_TagOptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name"}).
+		AddRow(1, "John Doe")
+
+	mock.ExpectQuery("SELECT").WillReturnRows(rows)
+
+	queryRows, err := db.Query("SELECT id, name FROM accounts")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer queryRows.Close()
+
+	var account struct {
+		ID     int    `db:"id,pk,auto"`
+		Name   string `db:"name"`
+		Secret string `db:"-"`
+	}
+	err = utils.ScanRows(queryRows, &account)
+	if err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+
+	if account.ID != 1 || account.Name != "John Doe" {
+		t.Errorf("account data mismatch: %+v", account)
+	}
+}
+
+type BaseModel struct {
+	ID        int    `db:"id"`
+	CreatedAt string `db:"created_at"`
+}
+
+type Article struct {
+	BaseModel
+	Title string `db:"title"`
+}
+
+func TestScanRows_EmbeddedStruct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "title", "created_at"}).
+		AddRow(1, "Hello", "2024-01-01")
+
+	mock.ExpectQuery("SELECT").WillReturnRows(rows)
+
+	queryRows, err := db.Query("SELECT id, title, created_at FROM articles")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer queryRows.Close()
+
+	var articles []Article
+	err = utils.ScanRows(queryRows, &articles)
+	if err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+
+	if len(articles) != 1 || articles[0].ID != 1 || articles[0].Title != "Hello" || articles[0].CreatedAt != "2024-01-01" {
+		t.Errorf("article data mismatch: %+v", articles)
+	}
+}
+
+func TestScanRows_IntoScalar(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John Doe"))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+
+	var count int
+	if err := scanQuery(t, db, &count); err != nil || count != 42 {
+		t.Errorf("expected count 42, got %d (%v)", count, err)
+	}
+
+	var name string
+	if err := scanQuery(t, db, &name); err != nil || name != "John Doe" {
+		t.Errorf("expected name 'John Doe', got '%s' (%v)", name, err)
+	}
+
+	var createdAt time.Time
+	if err := scanQuery(t, db, &createdAt); err != nil || !createdAt.Equal(now) {
+		t.Errorf("expected created_at %v, got %v (%v)", now, createdAt, err)
+	}
+}
+
+func TestScanRows_IntoPrimitiveSlice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("a@example.com").AddRow("b@example.com"))
+
+	var ids []int64
+	if err := scanQuery(t, db, &ids); err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Errorf("ids mismatch: %v", ids)
+	}
+
+	var emails []string
+	if err := scanQuery(t, db, &emails); err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+	if len(emails) != 2 || emails[1] != "b@example.com" {
+		t.Errorf("emails mismatch: %v", emails)
+	}
+}
+
+func TestScanRows_IntoMap(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"country", "total"}).AddRow([]byte("ID"), 12))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"country", "total"}).AddRow("ID", 12).AddRow("SG", 3))
+
+	var row map[string]any
+	if err := scanQuery(t, db, &row); err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+	if row["country"] != "ID" || row["total"] != int64(12) {
+		t.Errorf("row mismatch: %v", row)
+	}
+
+	var report []map[string]any
+	if err := scanQuery(t, db, &report); err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+	if len(report) != 2 || report[1]["country"] != "SG" {
+		t.Errorf("report mismatch: %v", report)
+	}
+}
+
+func TestScanRows_IntoStructPointerSlice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe").AddRow(2, "Jane Smith"))
+
+	var users []*User
+	if err := scanQuery(t, db, &users); err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+	if len(users) != 2 || users[0].ID != 1 || users[1].Name != "Jane Smith" {
+		t.Errorf("users mismatch: %+v", users)
+	}
+}
+
+func TestScanRows_ScalarColumns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+
+	var id int
+	if err := scanQuery(t, db, &id); err != constant.ErrScalarColumns {
+		t.Errorf("expected ErrScalarColumns, got %v", err)
+	}
+}
+
+// scanQuery runs a query against the next mocked result and scans it into dest
+func scanQuery(t *testing.T, db *sql.DB, dest any) error {
+	t.Helper()
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows.Close()
+	return utils.ScanRows(rows, dest)
+}
+
+type Author struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+type Post struct {
+	*BaseModel
+	Title    string  `db:"title"`
+	Author   Author  `db:"author" prefix:"author_"`
+	Reviewer *Author `db:"reviewer" prefix:"reviewer_"`
+}
+
+func TestScanRows_NestedStruct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "title", "author_id", "author_name", "reviewer_name"}).
+			AddRow(1, "Hello", 7, "John Doe", "Jane Smith"))
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"title", "author_name"}).
+			AddRow("Draft", "John Doe"))
+
+	var post Post
+	if err := scanQuery(t, db, &post); err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+	if post.BaseModel == nil || post.ID != 1 || post.Title != "Hello" {
+		t.Errorf("post data mismatch: %+v", post)
+	}
+	if post.Author.ID != 7 || post.Author.Name != "John Doe" {
+		t.Errorf("author data mismatch: %+v", post.Author)
+	}
+	if post.Reviewer == nil || post.Reviewer.Name != "Jane Smith" {
+		t.Errorf("reviewer data mismatch: %+v", post.Reviewer)
+	}
+
+	var draft Post
+	if err := scanQuery(t, db, &draft); err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+	if draft.BaseModel != nil || draft.Reviewer != nil {
+		t.Errorf("expected pointers without columns to stay nil, got %+v", draft)
+	}
+	if draft.Title != "Draft" || draft.Author.Name != "John Doe" {
+		t.Errorf("draft data mismatch: %+v", draft)
+	}
+}
+
+func TestScanRows_NestedStructNull(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	cols := []string{"id", "title", "author_id", "author_name", "reviewer_id", "reviewer_name"}
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows(cols).
+			AddRow(1, "Hello", 7, "John Doe", 8, "Jane Smith").
+			AddRow(2, "Draft", 7, "John Doe", nil, nil))
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows(cols).AddRow(nil, "Orphan", 7, "John Doe", 8, nil))
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows(cols).AddRow(nil, "Orphan", 7, "John Doe", 8, nil))
+
+	var posts []Post
+	if err := scanQuery(t, db, &posts); err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("expected 2 posts, got %d", len(posts))
+	}
+	if posts[0].Reviewer == nil || posts[0].Reviewer.ID != 8 {
+		t.Errorf("reviewer data mismatch: %+v", posts[0].Reviewer)
+	}
+	if posts[1].Reviewer != nil {
+		t.Errorf("expected nil reviewer for all-NULL columns, got %+v", posts[1].Reviewer)
+	}
+	if posts[1].BaseModel == nil || posts[1].ID != 2 {
+		t.Errorf("post data mismatch: %+v", posts[1])
+	}
+
+	var post Post
+	if err := scanQuery(t, db, &post); !errors.Is(err, constant.ErrConvert) {
+		t.Errorf("expected ErrConvert for a NULL name of a present reviewer, got %v", err)
+	}
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows.Close()
+	var orphan Post
+	if err := (utils.Scanner{ZeroOnNull: true}).Scan(rows, &orphan); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if orphan.BaseModel != nil {
+		t.Errorf("expected nil embedded model for a NULL id, got %+v", orphan.BaseModel)
+	}
+	if orphan.Reviewer == nil || orphan.Reviewer.ID != 8 || orphan.Reviewer.Name != "" {
+		t.Errorf("reviewer data mismatch: %+v", orphan.Reviewer)
+	}
+}
+
+type Account struct {
+	ID    int    `db:"id"`
+	Email string `db:"email,required"`
+	Name  string `db:"name"`
+}
+
+func TestScanner_Strict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		name    string
+		strict  bool
+		cols    []string
+		wantErr string
+	}{
+		{"lenient skips unmatched", false, []string{"id", "mail"}, ""},
+		{"strict matched", true, []string{"id", "email"}, ""},
+		{"strict matched upper case", true, []string{"ID", "EMAIL", "NAME"}, ""},
+		{"strict unmatched", true, []string{"id", "email", "nmae"}, "unmatched columns [nmae]"},
+		{"strict missing required", true, []string{"id", "name"}, "missing required columns [email]"},
+		{"strict both", true, []string{"id", "mail"}, "unmatched columns [mail], missing required columns [email]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := make([]driver.Value, len(tt.cols))
+			for i := range values {
+				values[i] = "1"
+			}
+			mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(tt.cols).AddRow(values...))
+
+			rows, err := db.Query("SELECT")
+			if err != nil {
+				t.Fatalf("failed to query: %v", err)
+			}
+			defer rows.Close()
+
+			var accounts []Account
+			err = utils.Scanner{Strict: tt.strict}.Scan(rows, &accounts)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Scan failed: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, constant.ErrStrictScan) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected ErrStrictScan with %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+type Profile struct {
+	ID        int        `db:"id"`
+	Bio       string     `db:"bio"`
+	BornAt    time.Time  `db:"born_at"`
+	DeletedAt *time.Time `db:"deleted_at"`
+	Nick      sql.NullString
+}
+
+func TestScanner_ZeroOnNull(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	cols := []string{"id", "bio", "born_at", "deleted_at", "nick"}
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(cols).AddRow(1, nil, nil, nil, nil))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(cols).
+		AddRow(1, nil, nil, nil, nil).
+		AddRow(2, "hello", now, now, "jo"))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow(nil).AddRow("John Doe"))
+
+	var profiles []Profile
+	if err := scanQuery(t, db, &profiles); err == nil {
+		t.Fatal("expected NULL into string to fail without ZeroOnNull")
+	}
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows.Close()
+
+	zero := utils.Scanner{ZeroOnNull: true}
+	profiles = nil
+	if err := zero.Scan(rows, &profiles); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("expected 2 profiles, got %d", len(profiles))
+	}
+	if p := profiles[0]; p.Bio != "" || !p.BornAt.IsZero() || p.DeletedAt != nil || p.Nick.Valid {
+		t.Errorf("expected zero values for NULL columns, got %+v", p)
+	}
+	if p := profiles[1]; p.Bio != "hello" || !p.BornAt.Equal(now) || p.DeletedAt == nil || p.Nick.String != "jo" {
+		t.Errorf("second profile mismatch: %+v", p)
+	}
+
+	scalarRows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer scalarRows.Close()
+
+	var names []string
+	if err := zero.Scan(scalarRows, &names); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(names) != 2 || names[0] != "" || names[1] != "John Doe" {
+		t.Errorf("names mismatch: %q", names)
+	}
+}
+
+type Settings struct {
+	Theme  string   `json:"theme"`
+	Alerts []string `json:"alerts"`
+}
+
+type Preference struct {
+	ID       int            `db:"id"`
+	Settings Settings       `db:"settings,json"`
+	Meta     map[string]any `db:"meta,json"`
+	Extra    *Settings      `db:"extra,json"`
+}
+
+func TestScanRows_JSONTag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "settings", "meta", "extra"}).
+		AddRow(1, []byte(`{"theme":"dark","alerts":["email"]}`), `{"beta":true}`, nil).
+		AddRow(2, nil, nil, `{"theme":"light"}`))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"settings"}).AddRow(`{"theme":`))
+
+	var prefs []Preference
+	if err := scanQuery(t, db, &prefs); err != nil {
+		t.Fatalf("ScanRows failed: %v", err)
+	}
+	if len(prefs) != 2 {
+		t.Fatalf("expected 2 preferences, got %d", len(prefs))
+	}
+	if p := prefs[0]; p.Settings.Theme != "dark" || len(p.Settings.Alerts) != 1 || p.Meta["beta"] != true || p.Extra != nil {
+		t.Errorf("first preference mismatch: %+v", p)
+	}
+	if p := prefs[1]; p.Settings.Theme != "" || p.Meta != nil || p.Extra == nil || p.Extra.Theme != "light" {
+		t.Errorf("second preference mismatch: %+v", p)
+	}
+
+	var broken Preference
+	if err := scanQuery(t, db, &broken); err == nil {
+		t.Error("expected invalid JSON to fail the scan")
+	}
+}
@@ -0,0 +1,69 @@
+package orm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/i-sub135/i-sub-orm/internal/expr"
+)
+
+type Article struct {
+	ID     int64    `db:"id"`
+	Title  string   `db:"title"`
+	Tags   []string `db:"tags"`
+	Scores []int64  `db:"scores"`
+}
+
+func TestPostgresArrays(t *testing.T) {
+	// "postgresql" is an alias of the postgres driver
+	for _, name := range []string{"postgres", "postgresql"} {
+		t.Run(name, func(t *testing.T) {
+			db, mock := newMockDB(t, name)
+
+			mock.ExpectQuery(`INSERT INTO articles \(title, tags, scores\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
+				WithArgs("Hello", `{"go","sql"}`, "{5,8}").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			mock.ExpectQuery(`SELECT \* FROM articles WHERE \(tags @> \$1 AND id = ANY\(\$2\)\)`).
+				WithArgs(`{"go"}`, "{1,2}").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "tags", "scores"}).
+					AddRow(1, "Hello", []byte(`{go,sql}`), []byte("{5,8}")))
+
+			a := Article{Title: "Hello", Tags: []string{"go", "sql"}, Scores: []int64{5, 8}}
+			if err := db.Create(&a); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+
+			var articles []Article
+			err := db.Table("articles").
+				Where(expr.And{expr.Contains{"tags": []string{"go"}}, expr.Any{"id": []int64{1, 2}}}).
+				Get(&articles)
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			want := []Article{{ID: 1, Title: "Hello", Tags: []string{"go", "sql"}, Scores: []int64{5, 8}}}
+			if !reflect.DeepEqual(articles, want) {
+				t.Errorf("articles = %+v, want %+v", articles, want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestArrays_AnyOutsidePostgres(t *testing.T) {
+	db, mock := newMockDB(t, "mysql")
+
+	mock.ExpectQuery(`SELECT \* FROM articles WHERE id IN \(\?,\?\)`).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var articles []Article
+	if err := db.Table("articles").Where(expr.Any{"id": []int64{1, 2}}).Get(&articles); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -1 +1,218 @@
 package orm
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/i-sub135/i-sub-orm/internal/driver"
+)
+
+// sortedColumns returns the keys of values in a stable order so the same
+// write always produces the same SQL text.
+func sortedColumns(values map[string]any) []string {
+	cols := make([]string, 0, len(values))
+	for col := range values {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+// buildInsert builds "INSERT INTO table (a, b) VALUES (?, ?)" for the given
+// columns. When returning is set the generated value of that column is read
+// back using the driver's syntax.
+func (q *Query) buildInsert(cols []string, returning string) string {
+	placeholders := strings.TrimRight(strings.Repeat("?, ", len(cols)), ", ")
+
+	sql := "INSERT INTO " + q.table + " (" + strings.Join(cols, ", ") + ")"
+	if returning != "" && driver.Driver(q.executor.driver) == driver.MSSQL {
+		sql += " OUTPUT INSERTED." + returning
+	}
+	sql += " VALUES (" + placeholders + ")"
+	if returning != "" && q.returnsInsert() {
+		sql += " RETURNING " + returning
+	}
+	return sql
+}
+
+// returnsInsert reports whether the driver reads generated values back with
+// INSERT ... RETURNING instead of LastInsertId.
+func (q *Query) returnsInsert() bool {
+	switch q.executor.driver {
+	case driver.Postgres.String(), "postgresql":
+		return true
+	default:
+		return false
+	}
+}
+
+// buildUpdate builds "UPDATE table SET a = ?, b = ? WHERE ..." and its
+// arguments; the SET arguments come before the WHERE arguments.
+func (q *Query) buildUpdate(values map[string]any) (string, []any) {
+	cols := sortedColumns(values)
+	sets := make([]string, 0, len(cols))
+	args := make([]any, 0, len(cols)+len(q.args))
+	for _, col := range cols {
+		sets = append(sets, col+" = ?")
+		args = append(args, values[col])
+	}
+	args = append(args, q.args...)
+
+	sql := "UPDATE " + q.table + " SET " + strings.Join(sets, ", ") + q.buildWhere()
+	return sql, args
+}
+
+// buildDelete builds "DELETE FROM table WHERE ..." and its arguments.
+func (q *Query) buildDelete() (string, []any) {
+	return "DELETE FROM " + q.table + q.buildWhere(), q.args
+}
+
+// buildWhere renders the WHERE clause with a leading space, or "" when the
+// query has no conditions.
+func (q *Query) buildWhere() string {
+	if len(q.where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.where, " AND ")
+}
+
+// orderClause is a single ORDER BY column
+type orderClause struct {
+	column string
+	desc   bool
+}
+
+func (o orderClause) String() string {
+	if o.desc {
+		return o.column + " DESC"
+	}
+	return o.column
+}
+
+// buildOrderLimit renders ORDER BY and LIMIT/OFFSET in the driver's syntax,
+// with a leading space, or "" when neither is set.
+func (q *Query) buildOrderLimit() string {
+	sql := ""
+	if len(q.orderBy) > 0 {
+		cols := make([]string, len(q.orderBy))
+		for i, o := range q.orderBy {
+			cols[i] = o.String()
+		}
+		sql = " ORDER BY " + strings.Join(cols, ", ")
+	}
+
+	if q.limit <= 0 && q.offset <= 0 {
+		return sql
+	}
+
+	limit, offset := strconv.Itoa(q.limit), strconv.Itoa(q.offset)
+	switch driver.Driver(q.executor.driver) {
+	case driver.MSSQL:
+		// OFFSET ... FETCH requires an ORDER BY clause
+		if sql == "" {
+			sql = " ORDER BY (SELECT NULL)"
+		}
+		sql += " OFFSET " + offset + " ROWS"
+		if q.limit > 0 {
+			sql += " FETCH NEXT " + limit + " ROWS ONLY"
+		}
+		return sql
+	case driver.MySQL:
+		// MySQL has no OFFSET without LIMIT; use the largest row count
+		if q.limit <= 0 {
+			limit = "18446744073709551615"
+		}
+	case driver.SQLite:
+		if q.limit <= 0 {
+			limit = "-1"
+		}
+	default:
+		if q.limit <= 0 {
+			return sql + " OFFSET " + offset
+		}
+	}
+
+	sql += " LIMIT " + limit
+	if q.offset > 0 {
+		sql += " OFFSET " + offset
+	}
+	return sql
+}
+
+// buildCount builds "SELECT COUNT(*) FROM table ... WHERE ..." for the query
+// source and conditions, counting groups through a derived table when the
+// query is grouped; the arguments of buildArgs apply unchanged.
+func (q *Query) buildCount() string {
+	if len(q.groupBy) == 0 {
+		return q.buildWith() + "SELECT COUNT(*)" + q.buildFrom() + q.buildWhere() + q.buildGroup()
+	}
+	sub := q.scalarQuery("1")
+	sub.ctes = nil
+	return q.buildWith() + "SELECT COUNT(*) FROM (" + sub.Build() + ") AS t"
+}
+
+// cte is a common table expression prepended by With/WithRecursive
+type cte struct {
+	name      string
+	sql       string
+	recursive bool
+}
+
+// buildWith renders the WITH clause with a trailing space, or "" when the
+// query has no common table expressions. SQL Server takes no RECURSIVE keyword.
+func (q *Query) buildWith() string {
+	if len(q.ctes) == 0 {
+		return ""
+	}
+
+	recursive := false
+	defs := make([]string, len(q.ctes))
+	for i, c := range q.ctes {
+		defs[i] = c.name + " AS (" + c.sql + ")"
+		recursive = recursive || c.recursive
+	}
+
+	sql := "WITH "
+	if recursive && driver.Driver(q.executor.driver) != driver.MSSQL {
+		sql += "RECURSIVE "
+	}
+	return sql + strings.Join(defs, ", ") + " "
+}
+
+// buildGroup renders GROUP BY and HAVING with a leading space, or "" when
+// the query is not grouped.
+func (q *Query) buildGroup() string {
+	sql := ""
+	if len(q.groupBy) > 0 {
+		sql += " GROUP BY " + strings.Join(q.groupBy, ", ")
+	}
+	if len(q.having) > 0 {
+		sql += " HAVING " + strings.Join(q.having, " AND ")
+	}
+	return sql
+}
+
+// buildFrom renders the FROM clause with the table alias and joins.
+func (q *Query) buildFrom() string {
+	sql := " FROM " + q.table
+	if q.alias != "" {
+		sql += " AS " + q.alias
+	}
+	for _, join := range q.joins {
+		sql += " " + join
+	}
+	return sql
+}
+
+// buildArgs returns the arguments of a SELECT in placeholder order: common
+// table expressions, the FROM subquery and join conditions first, then
+// WHERE and HAVING conditions.
+func (q *Query) buildArgs() []any {
+	args := make([]any, 0, len(q.cteArgs)+len(q.fromArgs)+len(q.joinArgs)+len(q.args)+len(q.havArgs))
+	args = append(args, q.cteArgs...)
+	args = append(args, q.fromArgs...)
+	args = append(args, q.joinArgs...)
+	args = append(args, q.args...)
+	return append(args, q.havArgs...)
+}
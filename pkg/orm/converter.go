@@ -0,0 +1,188 @@
+package orm
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+	"github.com/i-sub135/i-sub-orm/internal/utils"
+)
+
+// Converter converts values of type T from and to database values, for
+// types that do not implement sql.Scanner and driver.Valuer themselves.
+//
+// Scan receives the value returned by the driver (int64, float64, bool,
+// []byte, string, time.Time) or nil for NULL; a []byte is only valid until
+// Scan returns. Value returns the value bound when a T is passed as a query
+// argument. Either may be nil to convert in one direction only.
+type Converter[T any] struct {
+	Scan  func(src any) (T, error)
+	Value func(v T) (driver.Value, error)
+}
+
+// RegisterConverter registers c on db for values of type T; it is used when
+// scanning into T or *T and when binding a T or *T argument. It should be
+// called before the DB is shared between goroutines.
+//
+//	orm.RegisterConverter(db, orm.DecimalString[Money]())
+func RegisterConverter[T any](db *DB, c Converter[T]) {
+	var conv utils.Converter
+	if c.Scan != nil {
+		conv.Scan = func(src any) (any, error) {
+			return c.Scan(src)
+		}
+	}
+	if c.Value != nil {
+		conv.Value = func(v any) (driver.Value, error) {
+			return c.Value(v.(T))
+		}
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	db.executor.scanner.Converters = db.executor.scanner.Converters.With(t, conv)
+}
+
+// TimeUTC converts time.Time values to UTC both ways. Text timestamps, as
+// returned by some SQLite setups, are parsed as RFC 3339 or
+// "2006-01-02 15:04:05", assuming UTC when they carry no zone.
+func TimeUTC() Converter[time.Time] {
+	return Converter[time.Time]{
+		Scan: func(src any) (time.Time, error) {
+			switch v := src.(type) {
+			case nil:
+				return time.Time{}, nil
+			case time.Time:
+				return v.UTC(), nil
+			case []byte:
+				return parseTime(string(v))
+			case string:
+				return parseTime(v)
+			}
+			return time.Time{}, convertErr(src, "time.Time")
+		},
+		Value: func(v time.Time) (driver.Value, error) {
+			return v.UTC(), nil
+		},
+	}
+}
+
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, convertErr(s, "time.Time")
+}
+
+// DecimalString converts NUMERIC and DECIMAL columns into a string type T
+// without going through float64, so no precision is lost, e.g.
+//
+//	type Money string
+//	orm.RegisterConverter(db, orm.DecimalString[Money]())
+//
+// NULL scans as "". Values are validated as decimals before being bound.
+func DecimalString[T ~string]() Converter[T] {
+	return Converter[T]{
+		Scan: func(src any) (T, error) {
+			switch v := src.(type) {
+			case nil:
+				return "", nil
+			case []byte:
+				return T(v), nil
+			case string:
+				return T(v), nil
+			case int64:
+				return T(strconv.FormatInt(v, 10)), nil
+			case float64:
+				return T(strconv.FormatFloat(v, 'f', -1, 64)), nil
+			}
+			return "", convertErr(src, reflect.TypeOf(T("")).String())
+		},
+		Value: func(v T) (driver.Value, error) {
+			if !isDecimal(string(v)) {
+				return nil, convertErr(string(v), "decimal")
+			}
+			return string(v), nil
+		},
+	}
+}
+
+// isDecimal reports whether s is a plain decimal like "-12.50"
+func isDecimal(s string) bool {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	intPart, frac, _ := strings.Cut(s, ".")
+	if intPart == "" && frac == "" {
+		return false
+	}
+	return strings.Trim(intPart, "0123456789") == "" && strings.Trim(frac, "0123456789") == ""
+}
+
+// UUIDBytes converts UUIDs into a [16]byte type T, e.g. google/uuid.UUID,
+// stored as 16 raw bytes (MySQL BINARY(16)). It binds the raw bytes and
+// scans them as well as the text form; NULL scans as the zero UUID. Native
+// uuid columns, such as on Postgres, need UUIDText instead.
+func UUIDBytes[T ~[16]byte]() Converter[T] {
+	return Converter[T]{
+		Scan: scanUUID[T],
+		Value: func(v T) (driver.Value, error) {
+			return v[:], nil
+		},
+	}
+}
+
+// UUIDText is like UUIDBytes but binds the canonical hyphenated text form,
+// e.g. "9b2f3c1e-4a5d-4e6f-8a7b-1c2d3e4f5a6b", for native uuid columns and
+// text columns holding UUIDs.
+func UUIDText[T ~[16]byte]() Converter[T] {
+	return Converter[T]{
+		Scan: scanUUID[T],
+		Value: func(v T) (driver.Value, error) {
+			h := hex.EncodeToString(v[:])
+			return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+		},
+	}
+}
+
+// scanUUID scans 16 raw bytes or the text form of a UUID into T
+func scanUUID[T ~[16]byte](src any) (T, error) {
+	var id T
+	switch v := src.(type) {
+	case nil:
+		return id, nil
+	case []byte:
+		if len(v) == len(id) {
+			copy(id[:], v)
+			return id, nil
+		}
+		return parseUUID[T](string(v))
+	case string:
+		return parseUUID[T](v)
+	}
+	return id, convertErr(src, "UUID")
+}
+
+// parseUUID parses the canonical text form of a UUID, with or without
+// hyphens and braces.
+func parseUUID[T ~[16]byte](s string) (T, error) {
+	var id T
+	text := strings.ReplaceAll(strings.Trim(s, "{}"), "-", "")
+	if len(text) != 2*len(id) {
+		return id, convertErr(s, "UUID")
+	}
+	if _, err := hex.Decode(id[:], []byte(text)); err != nil {
+		return id, convertErr(s, "UUID")
+	}
+	return id, nil
+}
+
+func convertErr(src any, to string) error {
+	return fmt.Errorf("%w: %T %v to %s", constant.ErrConvert, src, src, to)
+}
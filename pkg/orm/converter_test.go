@@ -0,0 +1,180 @@
+package orm
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+)
+
+type Money string
+
+type UUID [16]byte
+
+type Status int
+
+const (
+	StatusActive Status = iota + 1
+	StatusBanned
+)
+
+// statusConverter stores Status as text
+var statusConverter = Converter[Status]{
+	Scan: func(src any) (Status, error) {
+		switch string(src.([]byte)) {
+		case "active":
+			return StatusActive, nil
+		case "banned":
+			return StatusBanned, nil
+		}
+		return 0, constant.ErrConvert
+	},
+	Value: func(v Status) (driver.Value, error) {
+		if v == StatusBanned {
+			return "banned", nil
+		}
+		return "active", nil
+	},
+}
+
+type Wallet struct {
+	ID        UUID      `db:"id,pk"`
+	Balance   Money     `db:"balance"`
+	Limit     *Money    `db:"limit_amount"`
+	Status    Status    `db:"status"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+var walletID = UUID{0x9b, 0x2f, 0x3c, 0x1e, 0x4a, 0x5d, 0x4e, 0x6f, 0x8a, 0x7b, 0x1c, 0x2d, 0x3e, 0x4f, 0x5a, 0x6b}
+
+func newConverterDB(t *testing.T, driver string) (*DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock := newMockDB(t, driver)
+	RegisterConverter(db, DecimalString[Money]())
+	RegisterConverter(db, UUIDBytes[UUID]())
+	RegisterConverter(db, TimeUTC())
+	RegisterConverter(db, statusConverter)
+	return db, mock
+}
+
+func TestConverter_Scan(t *testing.T) {
+	db, mock := newConverterDB(t, "postgres")
+	local := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+
+	mock.ExpectQuery(`SELECT \* FROM wallets`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "balance", "limit_amount", "status", "created_at"}).
+			AddRow("9b2f3c1e-4a5d-4e6f-8a7b-1c2d3e4f5a6b", []byte("1234567890.123456789"), nil, []byte("banned"), local).
+			AddRow(walletID[:], 12.5, "100", []byte("active"), "2024-05-01 05:00:00"))
+	mock.ExpectQuery(`SELECT balance FROM wallets`).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow([]byte("1.10")).AddRow(int64(3)))
+
+	var wallets []Wallet
+	if err := db.Table("wallets").Get(&wallets); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(wallets) != 2 {
+		t.Fatalf("expected 2 wallets, got %d", len(wallets))
+	}
+	w := wallets[0]
+	if w.ID != walletID || w.Balance != "1234567890.123456789" || w.Limit != nil || w.Status != StatusBanned {
+		t.Errorf("first wallet mismatch: %+v", w)
+	}
+	if w.CreatedAt.Location() != time.UTC || !w.CreatedAt.Equal(local) {
+		t.Errorf("expected created_at in UTC, got %v", w.CreatedAt)
+	}
+	w = wallets[1]
+	if w.ID != walletID || w.Balance != "12.5" || w.Limit == nil || *w.Limit != "100" || w.Status != StatusActive {
+		t.Errorf("second wallet mismatch: %+v", w)
+	}
+	if !w.CreatedAt.Equal(local) {
+		t.Errorf("expected text timestamp parsed as UTC, got %v", w.CreatedAt)
+	}
+
+	var balances []Money
+	if err := db.Table("wallets").Select("balance").Get(&balances); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(balances) != 2 || balances[0] != "1.10" || balances[1] != "3" {
+		t.Errorf("balances mismatch: %q", balances)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConverter_Bind(t *testing.T) {
+	db, mock := newConverterDB(t, "mysql")
+	local := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	limit := Money("50")
+
+	mock.ExpectExec("INSERT INTO wallets \\(id, balance, limit_amount, status, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs(walletID[:], "10.00", "50", "banned", local.UTC()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT \\* FROM wallets WHERE status = \\?").
+		WithArgs("active").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	w := Wallet{ID: walletID, Balance: "10.00", Limit: &limit, Status: StatusBanned, CreatedAt: local}
+	if err := db.Create(&w); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	var wallets []Wallet
+	if err := db.Table("wallets").Where("status = ?", StatusActive).Get(&wallets); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	if _, err := db.Table("wallets").Insert(map[string]any{"balance": Money("1e5")}); !errors.Is(err, constant.ErrConvert) {
+		t.Errorf("expected ErrConvert for invalid decimal, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConverter_BindPostgresUUID(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+	RegisterConverter(db, UUIDText[UUID]())
+
+	mock.ExpectExec(`INSERT INTO wallets \(id, balance, limit_amount, status, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\)`).
+		WithArgs("9b2f3c1e-4a5d-4e6f-8a7b-1c2d3e4f5a6b", "10.00", nil, 0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT \* FROM wallets WHERE id = \$1`).
+		WithArgs("9b2f3c1e-4a5d-4e6f-8a7b-1c2d3e4f5a6b").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("9b2f3c1e-4a5d-4e6f-8a7b-1c2d3e4f5a6b"))
+
+	w := Wallet{ID: walletID, Balance: "10.00"}
+	if err := db.Create(&w); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	var wallets []Wallet
+	if err := db.Table("wallets").Where("id = ?", walletID).Get(&wallets); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(wallets) != 1 || wallets[0].ID != walletID {
+		t.Errorf("wallets mismatch: %+v", wallets)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConverter_CreateReturningKey(t *testing.T) {
+	db, mock := newConverterDB(t, "postgres")
+
+	mock.ExpectQuery(`INSERT INTO wallets \(balance, limit_amount, status, created_at\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("9b2f3c1e-4a5d-4e6f-8a7b-1c2d3e4f5a6b"))
+
+	w := Wallet{Balance: "0", Status: StatusActive}
+	if err := db.Create(&w); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if w.ID != walletID {
+		t.Errorf("expected generated UUID, got %x", w.ID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
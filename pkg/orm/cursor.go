@@ -0,0 +1,209 @@
+package orm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+	"github.com/i-sub135/i-sub-orm/internal/expr"
+	"github.com/i-sub135/i-sub-orm/internal/utils"
+)
+
+// Cursor holds the opaque keyset cursors around a page of results.
+type Cursor struct {
+	Next string // pass to After to load the following page
+	Prev string // pass to Before to load the preceding page
+}
+
+// After switches the query to keyset pagination and returns the rows that
+// sort after cursor by the current ORDER BY columns. An empty cursor starts
+// from the first row.
+func (q *Query) After(cursor string) *Query {
+	q.cursor, q.before = cursor, false
+	return q
+}
+
+// Before is like After but returns the rows that sort before cursor.
+func (q *Query) Before(cursor string) *Query {
+	q.cursor, q.before = cursor, true
+	return q
+}
+
+// GetWithCursor loads a keyset page into dest, a pointer to a slice of
+// structs, and returns the cursors of its last and first rows. Both cursors
+// are empty when the page has no rows.
+func (q *Query) GetWithCursor(dest any) (Cursor, error) {
+	if len(q.orderBy) == 0 {
+		return Cursor{}, constant.ErrCursorOrder
+	}
+
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Pointer || destVal.Elem().Kind() != reflect.Slice {
+		return Cursor{}, constant.ErrDestinationType
+	}
+
+	// the page runs on a copy, so q can load further pages
+	page := *q
+	page.where, page.args = slices.Clip(q.where), slices.Clip(q.args)
+	if q.cursor != "" {
+		values, err := decodeCursor(q.cursor)
+		if err != nil {
+			return Cursor{}, err
+		}
+		if len(values) != len(q.orderBy) {
+			return Cursor{}, constant.ErrCursor
+		}
+		page.Where(q.keysetCondition(values))
+	}
+
+	// Before walks the index backwards, then restores the requested order
+	if q.before {
+		page.orderBy = make([]orderClause, len(q.orderBy))
+		for i, o := range q.orderBy {
+			page.orderBy[i] = orderClause{column: o.column, desc: !o.desc}
+		}
+	}
+	if err := page.Get(dest); err != nil {
+		return Cursor{}, err
+	}
+
+	rows := destVal.Elem()
+	if q.before {
+		swap := reflect.Swapper(rows.Interface())
+		for i, j := 0, rows.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+	if rows.Len() == 0 {
+		return Cursor{}, nil
+	}
+
+	var (
+		cur Cursor
+		err error
+	)
+	if cur.Prev, err = q.encodeCursor(rows.Index(0)); err != nil {
+		return Cursor{}, err
+	}
+	if cur.Next, err = q.encodeCursor(rows.Index(rows.Len() - 1)); err != nil {
+		return Cursor{}, err
+	}
+	return cur, nil
+}
+
+// keysetCondition builds the row comparison against the cursor values,
+// expanded so it works with mixed sort directions on every engine:
+//
+//	(a > ?) OR (a = ? AND b > ?) OR ...
+func (q *Query) keysetCondition(values []any) expr.Or {
+	cond := make(expr.Or, 0, len(q.orderBy))
+	for i, o := range q.orderBy {
+		op := ">"
+		if o.desc != q.before {
+			op = "<"
+		}
+
+		conds := make(expr.Conds, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, expr.Cond{Column: q.orderBy[j].column, Op: "=", Value: values[j]})
+		}
+		conds = append(conds, expr.Cond{Column: o.column, Op: op, Value: values[i]})
+		cond = append(cond, conds)
+	}
+	return cond
+}
+
+// encodeCursor reads the ORDER BY columns from row and encodes them as an
+// opaque cursor.
+func (q *Query) encodeCursor(row reflect.Value) (string, error) {
+	for row.Kind() == reflect.Pointer {
+		row = row.Elem()
+	}
+	if row.Kind() != reflect.Struct {
+		return "", constant.ErrDestinationType
+	}
+
+	model := q.executor.model(row.Type())
+
+	values := make([]any, len(q.orderBy))
+	for i, o := range q.orderBy {
+		// a qualified column like "u.id" maps to the "id" field
+		f, ok := model.Field(o.column[strings.LastIndex(o.column, ".")+1:])
+		if !ok {
+			return "", constant.ErrCursorColumn
+		}
+		if fv, ok := utils.LookupField(row, f.Index); ok {
+			values[i] = cursorValueOf(fv.Interface())
+		}
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor turns an encoded cursor back into its column values.
+func decodeCursor(cursor string) ([]any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, constant.ErrCursor
+	}
+
+	var raw []any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, constant.ErrCursor
+	}
+
+	for i, v := range raw {
+		switch v := v.(type) {
+		case json.Number:
+			if iv, err := v.Int64(); err == nil {
+				raw[i] = iv
+			} else if fv, err := v.Float64(); err == nil {
+				raw[i] = fv
+			}
+		case map[string]any:
+			if v["t"] != "time" {
+				continue
+			}
+			text, _ := v["v"].(string)
+			t, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return nil, constant.ErrCursor
+			}
+			raw[i] = t
+		}
+	}
+	return raw, nil
+}
+
+// cursorValue tags a cursor value whose type JSON would lose, so it binds
+// as the same type again, e.g. {"t":"time","v":"2024-01-01T10:00:00Z"}.
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value any    `json:"v"`
+}
+
+// cursorValueOf returns v as stored in a cursor. A time.Time is tagged so
+// it decodes back to a time.Time instead of its RFC 3339 text, which does
+// not compare correctly against engines storing times in another layout.
+func cursorValueOf(v any) any {
+	switch t := v.(type) {
+	case time.Time:
+		return cursorValue{Type: "time", Value: t}
+	case *time.Time:
+		if t != nil {
+			return cursorValue{Type: "time", Value: *t}
+		}
+	}
+	return v
+}
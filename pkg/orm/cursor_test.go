@@ -0,0 +1,128 @@
+package orm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+)
+
+func TestQuery_GetWithCursor(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT \* FROM users ORDER BY name, id DESC LIMIT 2`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "Alice").AddRow(1, "Bob"))
+
+	var page []User
+	cur, err := db.Table("users").OrderBy("name").OrderByDesc("id").Limit(2).GetWithCursor(&page)
+	if err != nil {
+		t.Fatalf("GetWithCursor failed: %v", err)
+	}
+	if len(page) != 2 || cur.Next == "" || cur.Prev == "" {
+		t.Fatalf("unexpected first page: %+v %+v", page, cur)
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM users WHERE active = \$1 AND \(name > \$2 OR \(name = \$3 AND id < \$4\)\) ORDER BY name, id DESC LIMIT 2`).
+		WithArgs(true, "Bob", "Bob", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "Carol"))
+
+	var next []User
+	if _, err := db.Table("users").Where("active = ?", true).OrderBy("name").OrderByDesc("id").Limit(2).After(cur.Next).GetWithCursor(&next); err != nil {
+		t.Fatalf("GetWithCursor after failed: %v", err)
+	}
+	if len(next) != 1 || next[0].Name != "Carol" {
+		t.Errorf("unexpected next page: %+v", next)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_GetWithCursorBefore(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	first, err := db.Table("users").OrderBy("id").encodeCursor(reflect.ValueOf(User{ID: 10}))
+	if err != nil {
+		t.Fatalf("encodeCursor failed: %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM users WHERE id < \$1 ORDER BY id DESC LIMIT 2`).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(9, "Ivy").AddRow(8, "Hank"))
+
+	var prev []User
+	cur, err := db.Table("users").OrderBy("id").Limit(2).Before(first).GetWithCursor(&prev)
+	if err != nil {
+		t.Fatalf("GetWithCursor before failed: %v", err)
+	}
+	if len(prev) != 2 || prev[0].ID != 8 || prev[1].ID != 9 {
+		t.Errorf("expected rows restored to ascending order, got %+v", prev)
+	}
+	if cur.Prev == "" || cur.Next == "" {
+		t.Errorf("expected both cursors, got %+v", cur)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_GetWithCursorErrors(t *testing.T) {
+	db, _ := newMockDB(t, "postgres")
+
+	var users []User
+	if _, err := db.Table("users").After("x").GetWithCursor(&users); err != constant.ErrCursorOrder {
+		t.Errorf("expected ErrCursorOrder, got %v", err)
+	}
+	if _, err := db.Table("users").OrderBy("id").After("%%%").GetWithCursor(&users); err != constant.ErrCursor {
+		t.Errorf("expected ErrCursor, got %v", err)
+	}
+}
+
+func TestQuery_GetWithCursorTime(t *testing.T) {
+	db, mock := newMockDB(t, "sqlite3")
+	at := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	cur, err := db.Table("order_items").OrderBy("created_at").encodeCursor(reflect.ValueOf(OrderItem{ID: 1, CreatedAt: at}))
+	if err != nil {
+		t.Fatalf("encodeCursor failed: %v", err)
+	}
+
+	// the cursor binds a time.Time, formatted by the driver, not RFC 3339 text
+	mock.ExpectQuery(`SELECT \* FROM order_items WHERE created_at > \? ORDER BY created_at LIMIT 1`).
+		WithArgs(at).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, at.Add(time.Hour)))
+
+	var items []OrderItem
+	if _, err := db.Table("order_items").OrderBy("created_at").Limit(1).After(cur).GetWithCursor(&items); err != nil {
+		t.Fatalf("GetWithCursor failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_GetWithCursorReuse(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	first, _ := db.Table("users").OrderBy("id").encodeCursor(reflect.ValueOf(User{ID: 2}))
+	second, _ := db.Table("users").OrderBy("id").encodeCursor(reflect.ValueOf(User{ID: 4}))
+
+	for _, id := range []int{2, 4} {
+		mock.ExpectQuery(`SELECT \* FROM users WHERE active = \$1 AND id > \$2 ORDER BY id LIMIT 2$`).
+			WithArgs(true, id).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id + 1))
+	}
+
+	q := db.Table("users").Where("active = ?", true).OrderBy("id").Limit(2)
+	for _, cur := range []string{first, second} {
+		var users []User
+		if _, err := q.After(cur).GetWithCursor(&users); err != nil {
+			t.Fatalf("GetWithCursor failed: %v", err)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -1,13 +1,22 @@
 package orm
 
 import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/i-sub135/i-sub-orm/internal/driver"
 	"github.com/i-sub135/i-sub-orm/internal/executor"
+	"github.com/i-sub135/i-sub-orm/internal/utils"
 )
 
 // executorWrapper is a wrapper around the executor.Executor struct
 type executorWrapper struct {
-	exec   *executor.Executor
-	driver string
+	exec    *executor.Executor
+	driver  string
+	naming  NamingStrategy
+	scanner utils.Scanner
+	logger  Logger
 }
 
 // newExecutorWrapper creates a new executorWrapper instance
@@ -16,8 +25,93 @@ func newExecutorWrapper(driver, dsn string) (*executorWrapper, error) {
 	if err != nil {
 		return nil, err
 	}
+	return wrapExecutor(exec, driver), nil
+}
+
+// wrapExecutor returns an executorWrapper for exec with the defaults of
+// driverName. Aliases such as "postgresql" are resolved here, so the
+// dialect checks only compare against the driver constants.
+func wrapExecutor(exec *executor.Executor, driverName string) *executorWrapper {
+	d := driver.Normalize(driverName)
 	return &executorWrapper{
-		exec:   exec,
-		driver: driver,
-	}, nil
+		exec:    exec,
+		driver:  d.String(),
+		naming:  SnakeCaseNaming{},
+		scanner: utils.Scanner{Arrays: d == driver.Postgres},
+	}
+}
+
+// withExecutor returns a copy of the wrapper that runs on exec
+func (e *executorWrapper) withExecutor(exec *executor.Executor) *executorWrapper {
+	c := *e
+	c.exec = exec
+	return &c
+}
+
+// model returns the field mapping of struct type t under the DB naming strategy
+func (e *executorWrapper) model(t reflect.Type) *utils.Model {
+	return e.scanner.Mapper.ModelOf(t)
+}
+
+// tableName returns the table of model: TableName() when implemented,
+// otherwise the name given by the DB naming strategy (User -> users).
+func (e *executorWrapper) tableName(model reflect.Value) string {
+	if t, ok := model.Interface().(Tabler); ok {
+		return t.TableName()
+	}
+	if t, ok := model.Addr().Interface().(Tabler); ok {
+		return t.TableName()
+	}
+	naming := e.naming
+	if naming == nil {
+		naming = SnakeCaseNaming{}
+	}
+	return naming.TableName(model.Type().Name())
+}
+
+// bind converts args of registered types, and slices to array text on
+// Postgres
+func (e *executorWrapper) bind(args []any) ([]any, error) {
+	args, err := e.scanner.Converters.Bind(args)
+	if err != nil {
+		return nil, err
+	}
+	if driver.Driver(e.driver) == driver.Postgres {
+		args = utils.BindArrays(args)
+	}
+	return args, nil
+}
+
+// log prints the statement to the DB logger, if one is set
+func (e *executorWrapper) log(query string, args []any) {
+	if e.logger != nil {
+		e.logger.Printf("Executing: %s Args: %v", query, args)
+	}
+}
+
+// query rebinds placeholders for the driver, converts args of registered
+// types and runs the query bound to ctx
+func (e *executorWrapper) query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	// Rebind placeholders for the specific driver
+	query = utils.RebindPlaceholder(query, e.driver)
+	args, err := e.bind(args)
+	if err != nil {
+		return nil, err
+	}
+
+	e.log(query, args)
+	return e.exec.QueryContext(ctx, query, args...)
+}
+
+// execute rebinds placeholders for the driver, converts args of registered
+// types and runs the statement bound to ctx
+func (e *executorWrapper) execute(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	query = utils.RebindPlaceholder(query, e.driver)
+	args, err := e.bind(args)
+	if err != nil {
+		return nil, err
+	}
+
+	e.log(query, args)
+	return e.exec.ExecContext(ctx, query, args...)
 }
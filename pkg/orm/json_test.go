@@ -0,0 +1,41 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Profile struct {
+	ID       int64          `db:"id"`
+	Settings map[string]any `db:"settings,json"`
+	Tags     []string       `db:"tags,json"`
+}
+
+func TestJSONTag_CreateAndGet(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`INSERT INTO profiles \(settings, tags\) VALUES \(\$1, \$2\) RETURNING id`).
+		WithArgs(`{"theme":"dark"}`, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT \* FROM profiles WHERE settings @> \$1`).
+		WithArgs(`{"theme":"dark"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "settings", "tags"}).
+			AddRow(1, []byte(`{"theme":"dark"}`), []byte(`["a","b"]`)))
+
+	p := Profile{Settings: map[string]any{"theme": "dark"}}
+	if err := db.Create(&p); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	var profiles []Profile
+	if err := db.Table("profiles").Where("settings @> :settings", p).Get(&profiles); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].Settings["theme"] != "dark" || len(profiles[0].Tags) != 2 {
+		t.Errorf("profiles mismatch: %+v", profiles)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,90 @@
+package orm
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+	"github.com/i-sub135/i-sub-orm/internal/driver"
+	"github.com/i-sub135/i-sub-orm/internal/utils"
+)
+
+// Tabler lets a model override the table name inferred from its type.
+type Tabler interface {
+	TableName() string
+}
+
+// Create inserts model, a pointer to a struct, into the table inferred from
+// its type. Columns come from the db tags; zero-valued primary key and
+// `auto` fields are left to the database, and a generated primary key is
+// written back into the struct. Fields tagged `json` are bound as JSON text.
+func (db *DB) Create(model any) error {
+	return db.CreateContext(context.Background(), model)
+}
+
+// CreateContext is like Create but runs the insert bound to ctx.
+func (db *DB) CreateContext(ctx context.Context, model any) error {
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return constant.ErrModel
+	}
+	v = v.Elem()
+
+	var (
+		cols []string
+		args []any
+		pk   reflect.Value
+		ret  string
+	)
+	for _, f := range db.executor.model(v.Type()).Fields {
+		fv, ok := utils.LookupField(v, f.Index)
+		if !ok || f.Nested {
+			continue
+		}
+		if (f.PK || f.Auto) && fv.IsZero() {
+			if f.PK {
+				pk, ret = fv, f.Column
+			}
+			continue
+		}
+		cols = append(cols, f.Column)
+		if f.JSON {
+			args = append(args, utils.JSONValue(fv))
+		} else {
+			args = append(args, fv.Interface())
+		}
+	}
+	if len(cols) == 0 {
+		return constant.ErrEmptyValues
+	}
+
+	q := db.Table(db.executor.tableName(v)).WithContext(ctx)
+	query := q.buildInsert(cols, ret)
+
+	// Postgres and SQL Server hand the generated key back as a result row
+	if ret != "" && (q.returnsInsert() || driver.Driver(db.executor.driver) == driver.MSSQL) {
+		rows, err := db.executor.query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		// scanned like a query result, so converters apply to the key
+		return db.executor.scanner.Scan(rows, pk.Addr().Interface())
+	}
+
+	res, err := db.executor.execute(ctx, query, args...)
+	if err != nil || ret == "" {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	switch pk.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		pk.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		pk.SetUint(uint64(id))
+	}
+	return nil
+}
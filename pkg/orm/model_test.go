@@ -0,0 +1,109 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+)
+
+type Account struct {
+	ID      int64  `db:"id"`
+	Email   string `db:"email"`
+	Version int    `db:"version,auto"`
+}
+
+type auditLog struct {
+	Key     string `db:"key,pk"`
+	Message string `db:"message"`
+}
+
+func (auditLog) TableName() string { return "audit_logs" }
+
+func TestCreate_Postgres(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`INSERT INTO accounts \(email\) VALUES \(\$1\) RETURNING id`).
+		WithArgs("john@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+
+	acc := Account{Email: "john@example.com"}
+	if err := db.Create(&acc); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if acc.ID != 42 {
+		t.Errorf("expected generated ID 42, got %d", acc.ID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreate_LastInsertId(t *testing.T) {
+	db, mock := newMockDB(t, "sqlite3")
+
+	mock.ExpectExec(`INSERT INTO accounts \(email, version\) VALUES \(\?, \?\)`).
+		WithArgs("john@example.com", 3).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+
+	acc := Account{Email: "john@example.com", Version: 3}
+	if err := db.Create(&acc); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if acc.ID != 9 {
+		t.Errorf("expected generated ID 9, got %d", acc.ID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreate_TableNameAndExplicitPK(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectExec(`INSERT INTO audit_logs \(key, message\) VALUES \(\$1, \$2\)`).
+		WithArgs("k1", "hello").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := db.Create(&auditLog{Key: "k1", Message: "hello"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreate_ErrorNotPointer(t *testing.T) {
+	db, _ := newMockDB(t, "postgres")
+
+	if err := db.Create(Account{}); err != constant.ErrModel {
+		t.Errorf("expected ErrModel, got %v", err)
+	}
+}
+
+type Timestamps struct {
+	CreatedAt string `db:"created_at"`
+}
+
+type Comment struct {
+	ID int64 `db:"id"`
+	*Timestamps
+	Body   string `db:"body"`
+	Author User   `db:"author" prefix:"author_"`
+}
+
+func TestCreate_SkipsNestedAndNilEmbedded(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`INSERT INTO comments \(body\) VALUES \(\$1\) RETURNING id`).
+		WithArgs("hi").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	c := Comment{Body: "hi", Author: User{ID: 3, Name: "John Doe"}}
+	if err := db.Create(&c); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,77 @@
+package orm
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/i-sub135/i-sub-orm/internal/utils"
+)
+
+// Named binds :name parameters of a raw condition or query by name:
+//
+//	Where("created_at BETWEEN :from AND :to", orm.Named{"from": a, "to": b})
+//
+// A struct (or pointer to struct) passed as the only argument binds by its
+// db tags the same way.
+type Named map[string]any
+
+// expandNamed rewrites the :name parameters of query into positional ?
+// placeholders when args is a single Named map or struct, and returns args
+// unchanged otherwise.
+func (e *executorWrapper) expandNamed(query string, args []any) (string, []any, error) {
+	lookup, ok := e.namedLookup(query, args)
+	if !ok {
+		return query, args, nil
+	}
+	return utils.ExpandNamed(query, e.driver, lookup)
+}
+
+// namedLookup returns the value lookup for a single Named or struct argument
+func (e *executorWrapper) namedLookup(query string, args []any) (func(string) (any, bool), bool) {
+	if len(args) != 1 {
+		return nil, false
+	}
+
+	var values map[string]any
+	switch a := args[0].(type) {
+	case Named:
+		values = a
+	case map[string]any:
+		values = a
+	case driver.Valuer, time.Time, *time.Time:
+		return nil, false
+	}
+	if values != nil {
+		return func(name string) (any, bool) {
+			v, ok := values[name]
+			return v, ok
+		}, true
+	}
+
+	// a struct binds by name only when the query has no positional ?
+	v := reflect.ValueOf(args[0])
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || strings.Contains(query, "?") {
+		return nil, false
+	}
+
+	model := e.model(v.Type())
+	return func(name string) (any, bool) {
+		f, ok := model.Field(name)
+		if !ok {
+			return nil, false
+		}
+		// a field behind a nil struct pointer binds as NULL
+		if fv, ok := utils.LookupField(v, f.Index); ok {
+			if f.JSON {
+				return utils.JSONValue(fv), true
+			}
+			return fv.Interface(), true
+		}
+		return nil, true
+	}, true
+}
@@ -0,0 +1,60 @@
+package orm
+
+import (
+	"strings"
+
+	"github.com/i-sub135/i-sub-orm/internal/utils"
+)
+
+// NamingStrategy names the table of a model type without a TableName method
+// and the column of a struct field without a db tag.
+type NamingStrategy interface {
+	TableName(typeName string) string
+	ColumnName(fieldName string) string
+}
+
+// SnakeCaseNaming is the default NamingStrategy: CreatedAt maps to
+// created_at and OrderItem to the order_items table.
+type SnakeCaseNaming struct {
+	TablePrefix   string // prepended to every inferred table name, e.g. "app_"
+	SingularTable bool   // keep table names singular: OrderItem -> order_item
+}
+
+// TableName returns the snake_case, plural and prefixed table name of typeName
+func (n SnakeCaseNaming) TableName(typeName string) string {
+	name := utils.SnakeCase(typeName)
+	if !n.SingularTable {
+		name = plural(name)
+	}
+	return n.TablePrefix + name
+}
+
+// ColumnName returns the snake_case column name of fieldName
+func (n SnakeCaseNaming) ColumnName(fieldName string) string {
+	return utils.SnakeCase(fieldName)
+}
+
+// plural applies the regular English plural rules to name
+func plural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsAny(name[len(name)-2:len(name)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "z"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
+
+// SetNamingStrategy sets how db names tables and columns that are not named
+// explicitly; it applies to scanning, Create, cursors and named parameters.
+// nil restores the default SnakeCaseNaming. It should be called before the
+// DB is shared between goroutines.
+func (db *DB) SetNamingStrategy(naming NamingStrategy) {
+	if naming == nil {
+		naming = SnakeCaseNaming{}
+	}
+	db.executor.naming = naming
+	db.executor.scanner.Mapper = utils.NewMapper(naming.ColumnName)
+}
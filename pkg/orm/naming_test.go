@@ -0,0 +1,140 @@
+package orm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type OrderItem struct {
+	ID        int64
+	ProductID int64
+	CreatedAt time.Time
+}
+
+func TestSnakeCaseNaming_TableName(t *testing.T) {
+	tests := []struct {
+		naming   SnakeCaseNaming
+		typeName string
+		want     string
+	}{
+		{SnakeCaseNaming{}, "User", "users"},
+		{SnakeCaseNaming{}, "OrderItem", "order_items"},
+		{SnakeCaseNaming{}, "Category", "categories"},
+		{SnakeCaseNaming{}, "Day", "days"},
+		{SnakeCaseNaming{}, "Address", "addresses"},
+		{SnakeCaseNaming{}, "Box", "boxes"},
+		{SnakeCaseNaming{SingularTable: true}, "OrderItem", "order_item"},
+		{SnakeCaseNaming{TablePrefix: "app_"}, "User", "app_users"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.naming.TableName(tt.typeName); got != tt.want {
+			t.Errorf("TableName(%q) with %+v = %q, want %q", tt.typeName, tt.naming, got, tt.want)
+		}
+	}
+}
+
+// upperNaming maps untagged fields to upper case columns in "tbl_" tables
+type upperNaming struct{}
+
+func (upperNaming) TableName(typeName string) string   { return "tbl_" + strings.ToLower(typeName) }
+func (upperNaming) ColumnName(fieldName string) string { return strings.ToUpper(fieldName) }
+
+func TestDB_NamingStrategy(t *testing.T) {
+	db, mock := newMockDB(t, "sqlite3")
+	now := time.Now()
+
+	mock.ExpectExec(`INSERT INTO order_items \(product_id, created_at\) VALUES \(\?, \?\)`).
+		WithArgs(7, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`SELECT \* FROM order_items`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "created_at"}).AddRow(1, 7, now))
+
+	item := OrderItem{ProductID: 7, CreatedAt: now}
+	if err := db.Create(&item); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	var items []OrderItem
+	if err := db.Table("order_items").Get(&items); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(items) != 1 || items[0].ProductID != 7 || !items[0].CreatedAt.Equal(now) {
+		t.Errorf("items mismatch: %+v", items)
+	}
+
+	db.SetNamingStrategy(upperNaming{})
+	mock.ExpectExec(`INSERT INTO tbl_orderitem \(PRODUCTID, CREATEDAT\) VALUES \(\?, \?\)`).
+		WithArgs(7, now).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectQuery(`SELECT \* FROM tbl_orderitem`).
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "PRODUCTID"}).AddRow(2, 7))
+
+	item = OrderItem{ProductID: 7, CreatedAt: now}
+	if err := db.Create(&item); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	items = nil
+	if err := db.Table("tbl_orderitem").Get(&items); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != 2 || items[0].ProductID != 7 {
+		t.Errorf("items mismatch: %+v", items)
+	}
+
+	// nil restores the default naming
+	db.SetNamingStrategy(nil)
+	mock.ExpectExec(`INSERT INTO order_items \(product_id, created_at\) VALUES \(\?, \?\)`).
+		WithArgs(7, now).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	item = OrderItem{ProductID: 7, CreatedAt: now}
+	if err := db.Create(&item); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// fieldNaming maps untagged fields to columns named exactly like the field
+type fieldNaming struct{}
+
+func (fieldNaming) TableName(typeName string) string   { return typeName }
+func (fieldNaming) ColumnName(fieldName string) string { return fieldName }
+
+func TestDB_NamingStrategyMixedCase(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+	db.SetNamingStrategy(fieldNaming{})
+
+	mock.ExpectExec(`UPDATE "OrderItem" SET "ProductID" = \$1 WHERE "ID" = \$2`).
+		WithArgs(7, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	item := OrderItem{ID: 1, ProductID: 7}
+	if _, err := db.Exec(`UPDATE "OrderItem" SET "ProductID" = :ProductID WHERE "ID" = :id`, item); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	cur, err := db.Table("OrderItem").OrderBy("ProductID").encodeCursor(reflect.ValueOf(item))
+	if err != nil {
+		t.Fatalf("encodeCursor failed: %v", err)
+	}
+	mock.ExpectQuery(`SELECT \* FROM OrderItem WHERE ProductID > \$1 ORDER BY ProductID LIMIT 1`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "ProductID"}).AddRow(2, 8))
+
+	var items []OrderItem
+	if _, err := db.Table("OrderItem").OrderBy("ProductID").Limit(1).After(cur).GetWithCursor(&items); err != nil {
+		t.Fatalf("GetWithCursor failed: %v", err)
+	}
+	if len(items) != 1 || items[0].ProductID != 8 {
+		t.Errorf("items mismatch: %+v", items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,59 @@
+package orm
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Null is a value of type T that may be NULL. It scans from and binds to
+// nullable columns, and marshals to JSON as the value or null:
+//
+//	type User struct {
+//		Nickname orm.Null[string]    `db:"nickname"`
+//		DeletedAt orm.Null[time.Time] `db:"deleted_at"`
+//	}
+type Null[T any] struct {
+	V     T
+	Valid bool // Valid is true if V is not NULL
+}
+
+// NewNull returns a valid Null holding v
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// Scan implements sql.Scanner
+func (n *Null[T]) Scan(value any) error {
+	return (*sql.Null[T])(n).Scan(value)
+}
+
+// Value implements driver.Valuer
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.V)
+}
+
+// MarshalJSON encodes V, or null when n is not valid
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalJSON decodes null as an invalid Null and anything else into V
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*n = Null[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
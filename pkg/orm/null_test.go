@@ -0,0 +1,104 @@
+package orm
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type Member struct {
+	ID       int64           `db:"id"`
+	Nickname Null[string]    `db:"nickname"`
+	Score    Null[int64]     `db:"score"`
+	LastSeen Null[time.Time] `db:"last_seen"`
+	Bio      string          `db:"bio"`
+}
+
+func TestNull_ScanAndJSON(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT \* FROM members`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "score", "last_seen", "bio"}).
+			AddRow(1, nil, nil, nil, "").
+			AddRow(2, "jo", 10, now, "hi"))
+
+	var members []Member
+	if err := db.Table("members").Get(&members); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(members) != 2 {
+		t.Fatalf("expected 2 members, got %d", len(members))
+	}
+	if m := members[0]; m.Nickname.Valid || m.Score.Valid || m.LastSeen.Valid {
+		t.Errorf("expected NULL columns to be invalid, got %+v", m)
+	}
+	if m := members[1]; m.Nickname != NewNull("jo") || m.Score != NewNull(int64(10)) || !m.LastSeen.V.Equal(now) {
+		t.Errorf("second member mismatch: %+v", m)
+	}
+
+	data, err := json.Marshal(members[0])
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"ID":1,"Nickname":null,"Score":null,"LastSeen":null,"Bio":""}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	var decoded Member
+	if err := json.Unmarshal([]byte(`{"Nickname":"jo","Score":null}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Nickname != NewNull("jo") || decoded.Score.Valid {
+		t.Errorf("decoded mismatch: %+v", decoded)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNull_Value(t *testing.T) {
+	db, mock := newMockDB(t, "mysql")
+
+	mock.ExpectExec("INSERT INTO members \\(nickname, score\\) VALUES \\(\\?, \\?\\)").
+		WithArgs(nil, int64(7)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	values := map[string]any{"nickname": Null[string]{}, "score": NewNull(7)}
+	if _, err := db.Table("members").Insert(values); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDB_ZeroOnNull(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name"}).AddRow(1, nil)
+	}
+	mock.ExpectQuery(`SELECT \* FROM users`).WillReturnRows(rows())
+	mock.ExpectQuery(`SELECT \* FROM users`).WillReturnRows(rows())
+
+	var users []User
+	if err := db.Table("users").Get(&users); err == nil {
+		t.Fatal("expected NULL into string to fail by default")
+	}
+
+	db.SetZeroOnNull(true)
+	users = nil
+	if err := db.Table("users").Get(&users); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(users) != 1 || users[0].ID != 1 || users[0].Name != "" {
+		t.Errorf("users mismatch: %+v", users)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
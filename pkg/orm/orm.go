@@ -21,6 +21,33 @@ func (db *DB) Close() error {
 	return db.executor.exec.Close()
 }
 
+// Logger receives the statements run by a DB, e.g. a *log.Logger.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// SetLogger logs every statement of db with its arguments to logger; nil,
+// the default, turns logging off. Arguments may hold sensitive values such
+// as passwords, so only log where that is acceptable.
+func (db *DB) SetLogger(logger Logger) {
+	db.executor.logger = logger
+}
+
+// SetStrict turns strict scanning on or off for every query of db. A strict
+// scan into a struct fails when a result column has no matching field, or a
+// field tagged "required" has no column, instead of skipping it. It should
+// be called before the DB is shared between goroutines.
+func (db *DB) SetStrict(strict bool) {
+	db.executor.scanner.Strict = strict
+}
+
+// SetZeroOnNull makes every query of db scan NULL into plain fields, such
+// as string or time.Time, as their zero value instead of failing. Use Null
+// or a pointer field to tell NULL apart from the zero value.
+func (db *DB) SetZeroOnNull(zero bool) {
+	db.executor.scanner.ZeroOnNull = zero
+}
+
 // Table initializes a new query for the specified table
 func (db *DB) Table(name string) *Query {
 	return &Query{
@@ -28,3 +55,9 @@ func (db *DB) Table(name string) *Query {
 		executor: db.executor,
 	}
 }
+
+// From initializes a new query reading from source, a table name or a
+// *Query used as a derived table (see Query.From)
+func (db *DB) From(source any) *Query {
+	return db.Table("").From(source)
+}
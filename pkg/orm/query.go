@@ -1,18 +1,40 @@
 package orm
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+	"github.com/i-sub135/i-sub-orm/internal/driver"
 	"github.com/i-sub135/i-sub-orm/internal/expr"
 	"github.com/i-sub135/i-sub-orm/internal/utils"
 )
 
 type Query struct {
+	ctes     []cte
+	cteArgs  []any
 	table    string
+	alias    string
 	fields   []string
+	fromArgs []any
+	joins    []string
+	joinArgs []any
 	where    []string
 	args     []any
+	groupBy  []string
+	having   []string
+	havArgs  []any
+	orderBy  []orderClause
+	limit    int
+	offset   int
+	cursor   string
+	before   bool
+	strict   bool
+	allRows  bool
+	ctx      context.Context
+	err      error
 	executor *executorWrapper
 }
 
@@ -23,22 +45,245 @@ func (q *Query) Select(cols ...string) *Query {
 
 // Flexible Where(): bisa string atau expr (Eq, Neq, dll)
 func (q *Query) Where(cond any, args ...any) *Query {
+	if sql, a := q.compile(cond, args); sql != "" {
+		q.where = append(q.where, sql)
+		q.args = append(q.args, a...)
+	}
+	return q
+}
+
+// compile turns a raw string condition with its args, or an expr condition,
+// into SQL for the query driver. Named parameters in raw strings are
+// expanded; an error is kept on the query and returned by its terminal method.
+func (q *Query) compile(cond any, args []any) (string, []any) {
 	switch c := cond.(type) {
 	case string:
-		q.where = append(q.where, c)
-		q.args = append(q.args, args...)
+		sql, a, err := q.executor.expandNamed(c, args)
+		q.setErr(err)
+		return sql, a
+	default:
+		sql, a, err := expr.CompileErr(c, driver.Driver(q.executor.driver))
+		q.setErr(err)
+		return sql, a
+	}
+}
+
+// With prepends the common table expression "name AS (sub)" to the query.
+// sub is a *Query, a *RawQuery, or a raw SQL string with its args, which may
+// be a single Named map or struct for :name parameters. name may list the
+// CTE columns, e.g. "tree(id, parent_id)", and can be referenced in Table,
+// From and Join.
+func (q *Query) With(name string, sub any, args ...any) *Query {
+	return q.with(name, sub, args, false)
+}
+
+// WithRecursive is like With but marks the query WITH RECURSIVE so sub may
+// reference name, typically as "anchor UNION ALL recursive step".
+func (q *Query) WithRecursive(name string, sub any, args ...any) *Query {
+	return q.with(name, sub, args, true)
+}
+
+func (q *Query) with(name string, sub any, args []any, recursive bool) *Query {
+	var sql string
+	switch s := sub.(type) {
+	case *Query:
+		sql, args = s.ToSQL()
+		q.setErr(s.err)
+	case *RawQuery:
+		sql, args = s.ToSQL()
+		q.setErr(s.err)
+	case string:
+		var err error
+		sql, args, err = q.executor.expandNamed(s, args)
+		q.setErr(err)
+	default:
+		q.setErr(fmt.Errorf("%w: %T", constant.ErrSource, sub))
+		return q
+	}
+	q.ctes = append(q.ctes, cte{name: name, sql: sql, recursive: recursive})
+	q.cteArgs = append(q.cteArgs, args...)
+	return q
+}
+
+// As sets the alias of the query table
+func (q *Query) As(alias string) *Query {
+	q.alias = alias
+	return q
+}
+
+// From sets the source of the query: a table name, or a *Query used as a
+// derived table named by its As alias ("sub" when it has none).
+func (q *Query) From(source any) *Query {
+	switch src := source.(type) {
+	case *Query:
+		sql, name, args := src.derived()
+		q.table, q.fromArgs = "("+sql+")", args
+		q.setErr(src.err)
+		if q.alias == "" {
+			q.alias = name
+		}
+	case string:
+		q.table, q.fromArgs = src, nil
 	default:
-		sql, a := expr.Compile(c)
-		if sql != "" {
-			q.where = append(q.where, sql)
-			q.args = append(q.args, a...)
+		q.setErr(fmt.Errorf("%w: %T", constant.ErrSource, source))
+	}
+	return q
+}
+
+// Join adds an INNER JOIN on table. The ON condition is either a raw string
+// with its args or an expr condition, e.g. expr.Eq{"p.user_id": expr.Col("u.id")}.
+// table is a table name that may carry an alias ("posts p" or "posts AS p"),
+// or a *Query joined as a derived table named by its As alias.
+func (q *Query) Join(table any, on any, args ...any) *Query {
+	return q.join("JOIN", table, on, args)
+}
+
+// LeftJoin adds a LEFT JOIN on table, see Join.
+func (q *Query) LeftJoin(table any, on any, args ...any) *Query {
+	return q.join("LEFT JOIN", table, on, args)
+}
+
+// RightJoin adds a RIGHT JOIN on table, see Join.
+func (q *Query) RightJoin(table any, on any, args ...any) *Query {
+	return q.join("RIGHT JOIN", table, on, args)
+}
+
+// CrossJoin adds a CROSS JOIN on table.
+func (q *Query) CrossJoin(table any) *Query {
+	return q.join("CROSS JOIN", table, nil, nil)
+}
+
+func (q *Query) join(kind string, table any, on any, args []any) *Query {
+	var clause string
+	switch t := table.(type) {
+	case *Query:
+		sql, name, subArgs := t.derived()
+		clause = kind + " (" + sql + ") AS " + name
+		q.joinArgs = append(q.joinArgs, subArgs...)
+		q.setErr(t.err)
+	case string:
+		clause = kind + " " + t
+	default:
+		q.setErr(fmt.Errorf("%w: %T", constant.ErrSource, table))
+		return q
+	}
+
+	if on != nil {
+		if cond, a := q.compile(on, args); cond != "" {
+			clause += " ON " + cond
+			q.joinArgs = append(q.joinArgs, a...)
 		}
 	}
+	q.joins = append(q.joins, clause)
+	return q
+}
+
+// derived builds the query for use as a derived table. Its As alias names
+// the derived table ("sub" when unset) instead of its own source.
+func (q *Query) derived() (string, string, []any) {
+	sub := *q
+	sub.alias = ""
+	sql, args := sub.ToSQL()
+
+	name := q.alias
+	if name == "" {
+		name = "sub"
+	}
+	return sql, name, args
+}
+
+// GroupBy groups the result by cols
+func (q *Query) GroupBy(cols ...string) *Query {
+	q.groupBy = append(q.groupBy, cols...)
+	return q
+}
+
+// Having filters groups; like Where it accepts a raw string with args or an
+// expr condition, e.g. Having(expr.Gt{"COUNT(*)": 5}).
+func (q *Query) Having(cond any, args ...any) *Query {
+	if sql, a := q.compile(cond, args); sql != "" {
+		q.having = append(q.having, sql)
+		q.havArgs = append(q.havArgs, a...)
+	}
+	return q
+}
+
+// OrderBy sorts the result by cols in ascending order
+func (q *Query) OrderBy(cols ...string) *Query {
+	for _, col := range cols {
+		q.orderBy = append(q.orderBy, orderClause{column: col})
+	}
+	return q
+}
+
+// OrderByDesc sorts the result by cols in descending order
+func (q *Query) OrderByDesc(cols ...string) *Query {
+	for _, col := range cols {
+		q.orderBy = append(q.orderBy, orderClause{column: col, desc: true})
+	}
+	return q
+}
+
+// Limit caps the number of returned rows; 0 means no limit
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// Offset skips the first n rows of the result
+func (q *Query) Offset(n int) *Query {
+	q.offset = n
+	return q
+}
+
+// setErr keeps the first error raised while building the query
+func (q *Query) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// WithContext binds ctx to the query; every terminal method honors its
+// cancellation and deadline.
+func (q *Query) WithContext(ctx context.Context) *Query {
+	q.ctx = ctx
+	return q
+}
+
+// Strict makes the query scan strictly even when its DB does not, see
+// DB.SetStrict.
+func (q *Query) Strict() *Query {
+	q.strict = true
+	return q
+}
+
+// AllRows lets Update and Delete run without a WHERE condition, on every row
+// of the table; they fail with constant.ErrNoWhere otherwise.
+func (q *Query) AllRows() *Query {
+	q.allRows = true
 	return q
 }
 
+// scanner returns the scanner of the DB, strict when the query asks for it
+func (q *Query) scanner() utils.Scanner {
+	s := q.executor.scanner
+	s.Strict = s.Strict || q.strict
+	return s
+}
+
+// context returns the context bound to the query, or context.Background()
+func (q *Query) context() context.Context {
+	if q.ctx == nil {
+		return context.Background()
+	}
+	return q.ctx
+}
+
 func (q *Query) Build() string {
-	sql := "SELECT "
+	// Add WITH clause
+	sql := q.buildWith()
+
+	sql += "SELECT "
 
 	// Handle fields
 	if len(q.fields) == 0 {
@@ -47,28 +292,222 @@ func (q *Query) Build() string {
 		sql += strings.Join(q.fields, ", ")
 	}
 
-	// Add FROM clause
-	sql += " FROM " + q.table
+	// Add FROM and JOIN clauses
+	sql += q.buildFrom()
 
 	// Add WHERE clause
-	if len(q.where) > 0 {
-		sql += " WHERE " + strings.Join(q.where, " AND ")
-	}
+	sql += q.buildWhere()
+
+	// Add GROUP BY and HAVING clauses
+	sql += q.buildGroup()
+
+	// Add ORDER BY and LIMIT/OFFSET clauses
+	sql += q.buildOrderLimit()
 
 	return sql
 }
 
+// ToSQL returns the SELECT built by the query with its arguments in
+// placeholder order. It lets a Query be used as a subquery inside expr
+// conditions, From and Join.
+func (q *Query) ToSQL() (string, []any) {
+	return q.Build(), q.buildArgs()
+}
+
+// Err returns the first error raised while building the query, the one its
+// terminal methods return. Queries embedding it fail with the same error.
+func (q *Query) Err() error {
+	return q.err
+}
+
 func (q *Query) Get(dest any) error {
-	query := q.Build()
+	if q.err != nil {
+		return q.err
+	}
+	rows, err := q.executor.query(q.context(), q.Build(), q.buildArgs()...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return q.scanner().Scan(rows, dest)
+}
 
-	// Rebind placeholders for the specific driver
-	query = utils.RebindPlaceholder(query, q.executor.driver)
+// GetContext is like Get but runs the query bound to ctx.
+func (q *Query) GetContext(ctx context.Context, dest any) error {
+	return q.WithContext(ctx).Get(dest)
+}
+
+// First scans the first row of the result into dest, which must be a pointer
+// to a struct. It returns sql.ErrNoRows when the query matches nothing.
+func (q *Query) First(dest any) error {
+	return q.Limit(1).Get(dest)
+}
+
+// FirstContext is like First but runs the query bound to ctx.
+func (q *Query) FirstContext(ctx context.Context, dest any) error {
+	return q.WithContext(ctx).First(dest)
+}
+
+// Insert inserts a single row built from values into the query table.
+// The returned sql.Result reports rows affected and, where the driver
+// supports it, the last insert id.
+func (q *Query) Insert(values map[string]any) (sql.Result, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if len(values) == 0 {
+		return nil, constant.ErrEmptyValues
+	}
+	cols := sortedColumns(values)
+	args := make([]any, 0, len(cols))
+	for _, col := range cols {
+		args = append(args, values[col])
+	}
+	return q.executor.execute(q.context(), q.buildInsert(cols, ""), args...)
+}
+
+// Update sets values on every row matched by the query conditions. A query
+// without conditions needs AllRows.
+func (q *Query) Update(values map[string]any) (sql.Result, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if len(values) == 0 {
+		return nil, constant.ErrEmptyValues
+	}
+	if len(q.where) == 0 && !q.allRows {
+		return nil, constant.ErrNoWhere
+	}
+	query, args := q.buildUpdate(values)
+	return q.executor.execute(q.context(), query, args...)
+}
+
+// Delete removes every row matched by the query conditions. A query
+// without conditions needs AllRows.
+func (q *Query) Delete() (sql.Result, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if len(q.where) == 0 && !q.allRows {
+		return nil, constant.ErrNoWhere
+	}
+	query, args := q.buildDelete()
+	return q.executor.execute(q.context(), query, args...)
+}
 
-	fmt.Println("Executing:", query, "Args:", q.args)
-	rows, err := q.executor.exec.Query(query, q.args...)
+// Paginate loads page (starting at 1) of size rows into dest and returns the
+// total number of rows matched by the query, ignoring ORDER BY and LIMIT.
+// size must be at least 1; the LIMIT and OFFSET of the page are not kept on q.
+func (q *Query) Paginate(page, size int, dest any) (int64, error) {
+	if size < 1 {
+		return 0, constant.ErrPageSize
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	total, err := q.Count()
+	if err != nil {
+		return 0, err
+	}
+	sub := *q
+	if err := sub.Limit(size).Offset((page - 1) * size).Get(dest); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// Count returns the number of rows matched by the query, or the number of
+// groups when the query has a GROUP BY. ORDER BY, LIMIT and OFFSET are ignored.
+func (q *Query) Count() (int64, error) {
+	var total int64
+	err := q.scan(q.buildCount(), q.buildArgs(), &total)
+	return total, err
+}
+
+// Sum scans SUM(col) over the rows matched by the query into dest, a
+// pointer to a scalar such as *int64, *float64 or *string (for NUMERIC).
+// dest is set to its zero value when there are no rows.
+func (q *Query) Sum(col string, dest any) error {
+	return q.aggregate("SUM", col, dest)
+}
+
+// Avg scans AVG(col) over the rows matched by the query into dest, like Sum.
+func (q *Query) Avg(col string, dest any) error {
+	return q.aggregate("AVG", col, dest)
+}
+
+// Min scans MIN(col) over the rows matched by the query into dest, which
+// may be of any scalar type the column scans into, e.g. *time.Time or
+// *string. dest is set to its zero value when there are no rows.
+func (q *Query) Min(col string, dest any) error {
+	return q.aggregate("MIN", col, dest)
+}
+
+// Max scans MAX(col) over the rows matched by the query into dest, like Min.
+func (q *Query) Max(col string, dest any) error {
+	return q.aggregate("MAX", col, dest)
+}
+
+// Exists reports whether the query matches at least one row.
+func (q *Query) Exists() (bool, error) {
+	sub := q.scalarQuery("1")
+	sub.limit = 1
+
+	var one int
+	err := q.scan(sub.Build(), sub.buildArgs(), &one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// aggregate runs fn(col) over the rows matched by the query conditions and
+// scans the result into dest, NULL as the zero value. GROUP BY is ignored
+// so the result is a single value.
+func (q *Query) aggregate(fn, col string, dest any) error {
+	if q.err != nil {
+		return q.err
+	}
+	sub := q.scalarQuery(fn + "(" + col + ")")
+	sub.groupBy, sub.having, sub.havArgs = nil, nil, nil
+
+	rows, err := q.executor.query(q.context(), sub.Build(), sub.buildArgs()...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	s := q.scanner()
+	s.ZeroOnNull = true
+	return s.Scan(rows, dest)
+}
+
+// scalarQuery returns a copy of the query selecting only column, without
+// ORDER BY, LIMIT and OFFSET.
+func (q *Query) scalarQuery(column string) *Query {
+	sub := *q
+	sub.fields = []string{column}
+	sub.orderBy, sub.limit, sub.offset = nil, 0, 0
+	return &sub
+}
+
+// scan runs query and scans the single column of its first row into dest
+func (q *Query) scan(query string, args []any, dest any) error {
+	if q.err != nil {
+		return q.err
+	}
+	rows, err := q.executor.query(q.context(), query, args...)
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
-	return utils.ScanRows(rows, dest)
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	return rows.Scan(dest)
 }
@@ -0,0 +1,778 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+	"github.com/i-sub135/i-sub-orm/internal/executor"
+	"github.com/i-sub135/i-sub-orm/internal/expr"
+)
+
+type User struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+func newMockDB(t *testing.T, driver string) (*DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &DB{executor: wrapExecutor(&executor.Executor{DB: db}, driver)}, mock
+}
+
+func TestQuery_GetContext(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT \* FROM users WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+
+	var users []User
+	if err := db.Table("users").Where("id = ?", 1).GetContext(context.Background(), &users); err != nil {
+		t.Fatalf("GetContext failed: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "John Doe" {
+		t.Errorf("users mismatch: %+v", users)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_FirstContextCanceled(t *testing.T) {
+	db, _ := newMockDB(t, "postgres")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var user User
+	err := db.Table("users").FirstContext(ctx, &user)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestQuery_Insert(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectExec(`INSERT INTO users \(email, name\) VALUES \(\$1, \$2\)`).
+		WithArgs("john@example.com", "John Doe").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	res, err := db.Table("users").Insert(map[string]any{"name": "John Doe", "email": "john@example.com"})
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if id, _ := res.LastInsertId(); id != 7 {
+		t.Errorf("expected last insert id 7, got %d", id)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_Update(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectExec(`UPDATE users SET name = \$1 WHERE id = \$2`).
+		WithArgs("Jane Doe", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := db.Table("users").Where(expr.Eq{"id": 1}).Update(map[string]any{"name": "Jane Doe"})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		t.Errorf("expected 1 row affected, got %d", n)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_UpdateEmptyValues(t *testing.T) {
+	db, _ := newMockDB(t, "postgres")
+
+	if _, err := db.Table("users").Update(nil); err != constant.ErrEmptyValues {
+		t.Errorf("expected ErrEmptyValues, got %v", err)
+	}
+}
+
+func TestQuery_Delete(t *testing.T) {
+	db, mock := newMockDB(t, "mysql")
+
+	mock.ExpectExec(`DELETE FROM users WHERE id = \?`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if _, err := db.Table("users").Where("id = ?", 1).Delete(); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_WhereOr(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT \* FROM users WHERE \(status = \$1 OR \(role = \$2 AND active = \$3\)\) AND deleted = \$4`).
+		WithArgs("active", "admin", true, false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	var users []User
+	err := db.Table("users").
+		Where(expr.Or{expr.Eq{"status": "active"}, expr.And{expr.Eq{"role": "admin"}, expr.Eq{"active": true}}}).
+		Where("deleted = ?", false).
+		Get(&users)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_BuildOrderLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		build  func(q *Query) *Query
+		want   string
+	}{
+		{
+			name:   "order by asc and desc",
+			driver: "postgres",
+			build:  func(q *Query) *Query { return q.OrderBy("name").OrderByDesc("created_at") },
+			want:   "SELECT * FROM users ORDER BY name, created_at DESC",
+		},
+		{
+			name:   "postgres limit offset",
+			driver: "postgres",
+			build:  func(q *Query) *Query { return q.OrderBy("id").Limit(10).Offset(20) },
+			want:   "SELECT * FROM users ORDER BY id LIMIT 10 OFFSET 20",
+		},
+		{
+			name:   "postgres offset only",
+			driver: "postgres",
+			build:  func(q *Query) *Query { return q.Offset(5) },
+			want:   "SELECT * FROM users OFFSET 5",
+		},
+		{
+			name:   "mysql offset only",
+			driver: "mysql",
+			build:  func(q *Query) *Query { return q.Offset(5) },
+			want:   "SELECT * FROM users LIMIT 18446744073709551615 OFFSET 5",
+		},
+		{
+			name:   "sqlite offset only",
+			driver: "sqlite3",
+			build:  func(q *Query) *Query { return q.Offset(5) },
+			want:   "SELECT * FROM users LIMIT -1 OFFSET 5",
+		},
+		{
+			name:   "mssql offset fetch",
+			driver: "sqlserver",
+			build:  func(q *Query) *Query { return q.OrderBy("id").Limit(10).Offset(20) },
+			want:   "SELECT * FROM users ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+		},
+		{
+			name:   "mssql limit without order",
+			driver: "sqlserver",
+			build:  func(q *Query) *Query { return q.Limit(1) },
+			want:   "SELECT * FROM users ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newMockDB(t, tt.driver)
+
+			if got := tt.build(db.Table("users")).Build(); got != tt.want {
+				t.Errorf("Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_Paginate(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users WHERE active = \$1`).
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(25))
+	mock.ExpectQuery(`SELECT \* FROM users WHERE active = \$1 ORDER BY id LIMIT 10 OFFSET 10`).
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(11, "John Doe"))
+
+	var users []User
+	total, err := db.Table("users").Where("active = ?", true).OrderBy("id").Paginate(2, 10, &users)
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if total != 25 {
+		t.Errorf("expected total 25, got %d", total)
+	}
+	if len(users) != 1 || users[0].ID != 11 {
+		t.Errorf("users mismatch: %+v", users)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_PaginateSize(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	var users []User
+	for _, size := range []int{0, -1} {
+		if _, err := db.Table("users").Paginate(1, size, &users); !errors.Is(err, constant.ErrPageSize) {
+			t.Errorf("Paginate(1, %d) error = %v, want ErrPageSize", size, err)
+		}
+	}
+
+	// the page LIMIT and OFFSET do not stick to the query
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`SELECT \* FROM users LIMIT 2 OFFSET 2`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(`SELECT \* FROM users$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	q := db.Table("users")
+	if _, err := q.Paginate(2, 2, &users); err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if err := q.Get(&users); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_Join(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	type Base struct {
+		ID int `db:"id"`
+	}
+	type Post struct {
+		Base
+		Title  string `db:"title"`
+		Author string `db:"author"`
+	}
+
+	mock.ExpectQuery(`SELECT p.id, p.title, u.name AS author FROM posts AS p `+
+		`JOIN users u ON u.id = p.user_id `+
+		`LEFT JOIN tags t ON t.post_id = p.id AND t.kind = \$1 `+
+		`CROSS JOIN settings `+
+		`WHERE u.active = \$2`).
+		WithArgs("topic", true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author"}).AddRow(5, "Hello", "John Doe"))
+
+	var posts []Post
+	err := db.Table("posts").As("p").
+		Select("p.id", "p.title", "u.name AS author").
+		Join("users u", expr.Eq{"u.id": expr.Col("p.user_id")}).
+		LeftJoin("tags t", "t.post_id = p.id AND t.kind = ?", "topic").
+		CrossJoin("settings").
+		Where(expr.Eq{"u.active": true}).
+		Get(&posts)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != 5 || posts[0].Title != "Hello" || posts[0].Author != "John Doe" {
+		t.Errorf("posts mismatch: %+v", posts)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_GroupByHaving(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT country, COUNT\(\*\) AS total FROM users WHERE active = \$1 GROUP BY country HAVING COUNT\(\*\) > \$2 AND MAX\(age\) < \$3 ORDER BY total DESC`).
+		WithArgs(true, 5, 90).
+		WillReturnRows(sqlmock.NewRows([]string{"country", "total"}).AddRow("ID", 12))
+
+	type row struct {
+		Country string `db:"country"`
+		Total   int    `db:"total"`
+	}
+	var rows []row
+	err := db.Table("users").
+		Select("country", "COUNT(*) AS total").
+		Where("active = ?", true).
+		GroupBy("country").
+		Having(expr.Gt{"COUNT(*)": 5}).
+		Having("MAX(age) < ?", 90).
+		OrderByDesc("total").
+		Get(&rows)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Country != "ID" || rows[0].Total != 12 {
+		t.Errorf("rows mismatch: %+v", rows)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_Count(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users WHERE active = \$1`).
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM \(SELECT 1 FROM users GROUP BY country HAVING COUNT\(\*\) > \$1\) AS t`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	if n, err := db.Table("users").Where("active = ?", true).OrderBy("id").Limit(1).Count(); err != nil || n != 3 {
+		t.Errorf("Count() = %d, %v; want 3", n, err)
+	}
+	if n, err := db.Table("users").GroupBy("country").Having("COUNT(*) > ?", 1).Count(); err != nil || n != 2 {
+		t.Errorf("grouped Count() = %d, %v; want 2", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_Aggregates(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT SUM\(amount\) FROM orders WHERE status = \$1`).
+		WithArgs("paid").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(150.5))
+	mock.ExpectQuery(`SELECT AVG\(amount\) FROM orders`).
+		WillReturnRows(sqlmock.NewRows([]string{"avg"}).AddRow(nil))
+	mock.ExpectQuery(`SELECT MIN\(amount\) FROM orders`).
+		WillReturnRows(sqlmock.NewRows([]string{"min"}).AddRow(1))
+	mock.ExpectQuery(`SELECT MAX\(amount\) FROM orders`).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(99))
+	mock.ExpectQuery(`SELECT SUM\(views\) FROM posts`).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(int64(1<<53 + 1)))
+
+	var sum, avg float64
+	if err := db.Table("orders").Where("status = ?", "paid").Sum("amount", &sum); err != nil || sum != 150.5 {
+		t.Errorf("Sum() = %v, %v; want 150.5", sum, err)
+	}
+	avg = 3
+	if err := db.Table("orders").Avg("amount", &avg); err != nil || avg != 0 {
+		t.Errorf("Avg() of NULL = %v, %v; want 0", avg, err)
+	}
+	var min, max int
+	if err := db.Table("orders").Min("amount", &min); err != nil || min != 1 {
+		t.Errorf("Min() = %v, %v; want 1", min, err)
+	}
+	if err := db.Table("orders").Max("amount", &max); err != nil || max != 99 {
+		t.Errorf("Max() = %v, %v; want 99", max, err)
+	}
+	var views int64
+	if err := db.Table("posts").Sum("views", &views); err != nil || views != 1<<53+1 {
+		t.Errorf("Sum() of BIGINT = %v, %v; want %d", views, err, int64(1<<53+1))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_AggregatesNonNumeric(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+	first := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT MIN\(created_at\) FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"min"}).AddRow(first))
+	mock.ExpectQuery(`SELECT MAX\(name\) FROM users WHERE active = \$1`).
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("Zoe"))
+	mock.ExpectQuery(`SELECT MAX\(created_at\) FROM users WHERE active = \$1`).
+		WithArgs(false).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil))
+	mock.ExpectQuery(`SELECT MAX\(created_at\) FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil))
+
+	var oldest time.Time
+	if err := db.Table("users").Min("created_at", &oldest); err != nil || !oldest.Equal(first) {
+		t.Errorf("Min() of timestamp = %v, %v; want %v", oldest, err, first)
+	}
+	var last string
+	if err := db.Table("users").Where("active = ?", true).Max("name", &last); err != nil || last != "Zoe" {
+		t.Errorf("Max() of text = %q, %v; want Zoe", last, err)
+	}
+	latest := first
+	if err := db.Table("users").Where("active = ?", false).Max("created_at", &latest); err != nil || !latest.IsZero() {
+		t.Errorf("Max() of no rows = %v, %v; want zero time", latest, err)
+	}
+	var nullable sql.NullTime
+	if err := db.Table("users").Max("created_at", &nullable); err != nil || nullable.Valid {
+		t.Errorf("Max() into NullTime = %v, %v; want invalid", nullable, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_Exists(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT 1 FROM users WHERE email = \$1 LIMIT 1`).
+		WithArgs("john@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+	mock.ExpectQuery(`SELECT 1 FROM users WHERE email = \$1 LIMIT 1`).
+		WithArgs("nobody@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+
+	if ok, err := db.Table("users").Where("email = ?", "john@example.com").Exists(); err != nil || !ok {
+		t.Errorf("Exists() = %v, %v; want true", ok, err)
+	}
+	if ok, err := db.Table("users").Where("email = ?", "nobody@example.com").Exists(); err != nil || ok {
+		t.Errorf("Exists() = %v, %v; want false", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_Subqueries(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	paid := db.Table("orders").Select("user_id").Where("status = ?", "paid")
+	totals := db.Table("orders").Select("user_id", "SUM(amount) AS total").Where("amount > ?", 10).GroupBy("user_id").As("o")
+	latest := db.Table("logins").Select("MAX(at)").Where("logins.user_id = users.id")
+
+	mock.ExpectQuery(`SELECT users.id, users.name FROM users `+
+		`JOIN \(SELECT user_id, SUM\(amount\) AS total FROM orders WHERE amount > \$1 GROUP BY user_id\) AS o ON o.user_id = users.id AND o.total > \$2 `+
+		`WHERE id IN \(SELECT user_id FROM orders WHERE status = \$3\) AND last_login = \(SELECT MAX\(at\) FROM logins WHERE logins.user_id = users.id\) AND active = \$4`).
+		WithArgs(10, 100, "paid", true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+
+	var users []User
+	err := db.Table("users").
+		Select("users.id", "users.name").
+		Join(totals, "o.user_id = users.id AND o.total > ?", 100).
+		Where(expr.In{"id": {paid}}).
+		Where(expr.Eq{"last_login": latest}).
+		Where("active = ?", true).
+		Get(&users)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_FromSubquery(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	recent := db.Table("orders").Where("created_at > ?", "2024-01-01").As("r")
+
+	mock.ExpectQuery(`SELECT r.user_id FROM \(SELECT \* FROM orders WHERE created_at > \$1\) AS r WHERE r.amount > \$2`).
+		WithArgs("2024-01-01", 50).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	var rows []struct {
+		UserID int `db:"user_id"`
+	}
+	if err := db.From(recent).Select("r.user_id").Where("r.amount > ?", 50).Get(&rows); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_With(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	active := db.Table("users").Select("id", "name").Where("active = ?", true)
+
+	mock.ExpectQuery(`WITH active_users AS \(SELECT id, name FROM users WHERE active = \$1\) `+
+		`SELECT a.id, a.name FROM active_users AS a JOIN orders o ON o.user_id = a.id WHERE o.amount > \$2`).
+		WithArgs(true, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+
+	var users []User
+	err := db.Table("active_users").As("a").
+		With("active_users", active).
+		Select("a.id", "a.name").
+		Join("orders o", "o.user_id = a.id").
+		Where("o.amount > ?", 10).
+		Get(&users)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_WithRecursive(t *testing.T) {
+	const tree = "SELECT id, parent_id FROM categories WHERE id = ? " +
+		"UNION ALL SELECT c.id, c.parent_id FROM categories c JOIN tree ON c.parent_id = tree.id"
+
+	tests := []struct {
+		name   string
+		driver string
+		want   string
+	}{
+		{
+			name:   "postgres",
+			driver: "postgres",
+			want:   "WITH RECURSIVE tree(id, parent_id) AS (" + tree + ") SELECT * FROM tree WHERE id != ?",
+		},
+		{
+			name:   "mssql has no RECURSIVE keyword",
+			driver: "sqlserver",
+			want:   "WITH tree(id, parent_id) AS (" + tree + ") SELECT * FROM tree WHERE id != ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newMockDB(t, tt.driver)
+
+			sql, args := db.Table("tree").WithRecursive("tree(id, parent_id)", tree, 1).Where("id != ?", 2).ToSQL()
+			if sql != tt.want {
+				t.Errorf("ToSQL() sql = %v, want %v", sql, tt.want)
+			}
+			if !reflect.DeepEqual(args, []any{1, 2}) {
+				t.Errorf("ToSQL() args = %v, want [1 2]", args)
+			}
+		})
+	}
+}
+
+func TestQuery_CountWith(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`WITH recent AS \(SELECT \* FROM orders WHERE created_at > \$1\) SELECT COUNT\(\*\) FROM \(SELECT 1 FROM recent GROUP BY user_id\) AS t`).
+		WithArgs("2024-01-01").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	n, err := db.Table("recent").
+		With("recent", db.Table("orders").Where("created_at > ?", "2024-01-01")).
+		GroupBy("user_id").
+		Count()
+	if err != nil || n != 4 {
+		t.Errorf("Count() = %d, %v; want 4", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_WhereNamed(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT \* FROM orders WHERE created_at BETWEEN \$1 AND \$2 AND user_id = \$3 AND name = \$4`).
+		WithArgs("2024-01-01", "2024-02-01", 7, "John Doe").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	var rows []User
+	err := db.Table("orders").
+		Where("created_at BETWEEN :from AND :to", Named{"from": "2024-01-01", "to": "2024-02-01"}).
+		Where("user_id = :id AND name = :name", User{ID: 7, Name: "John Doe"}).
+		Get(&rows)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_WhereNamedMissing(t *testing.T) {
+	db, _ := newMockDB(t, "postgres")
+
+	var rows []User
+	err := db.Table("orders").Where("user_id = :id", Named{"user": 1}).Get(&rows)
+	if !errors.Is(err, constant.ErrNamedParam) {
+		t.Errorf("expected ErrNamedParam, got %v", err)
+	}
+}
+
+func TestQuery_Strict(t *testing.T) {
+	db, mock := newMockDB(t, "mysql")
+
+	mock.ExpectQuery("SELECT \\* FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "John Doe", "john@example.com"))
+	mock.ExpectQuery("SELECT \\* FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "John Doe", "john@example.com"))
+
+	var users []User
+	if err := db.Table("users").Strict().Get(&users); !errors.Is(err, constant.ErrStrictScan) {
+		t.Errorf("expected ErrStrictScan from Query.Strict, got %v", err)
+	}
+
+	db.SetStrict(true)
+	err := db.Table("users").Get(&users)
+	if !errors.Is(err, constant.ErrStrictScan) || !strings.Contains(err.Error(), "unmatched columns [email]") {
+		t.Errorf("expected ErrStrictScan listing email, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// recordLogger collects the lines printed by the DB logger
+type recordLogger struct {
+	lines []string
+}
+
+func (l *recordLogger) Printf(format string, v ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestDB_Logger(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT \* FROM users WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT \* FROM users WHERE id = \$1`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	var users []User
+	if err := db.Table("users").Where("id = ?", 1).Get(&users); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	logger := &recordLogger{}
+	db.SetLogger(logger)
+	if err := db.Table("users").Where("id = ?", 2).Get(&users); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	want := []string{"Executing: SELECT * FROM users WHERE id = $1 Args: [2]"}
+	if !reflect.DeepEqual(logger.lines, want) {
+		t.Errorf("logged %q, want %q", logger.lines, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDB_LoggerWrites(t *testing.T) {
+	db, mock := newMockDB(t, "mysql")
+	logger := &recordLogger{}
+	db.SetLogger(logger)
+
+	mock.ExpectExec("DELETE FROM users WHERE id = \\?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if _, err := db.Table("users").Where("id = ?", 1).Delete(); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	want := []string{"Executing: DELETE FROM users WHERE id = ? Args: [1]"}
+	if !reflect.DeepEqual(logger.lines, want) {
+		t.Errorf("logged %q, want %q", logger.lines, want)
+	}
+}
+
+func TestQuery_WhereInvalidCond(t *testing.T) {
+	db, _ := newMockDB(t, "mysql")
+
+	var users []User
+	err := db.Table("users").Where(expr.Cond{Column: "id", Op: "IN", Value: 1}).Get(&users)
+	if !errors.Is(err, constant.ErrInValue) {
+		t.Errorf("expected ErrInValue, got %v", err)
+	}
+}
+
+func TestQuery_WhereUnsupportedCond(t *testing.T) {
+	db, _ := newMockDB(t, "mysql")
+
+	if _, err := db.Table("users").Where(map[string]any{"id": 1}).Delete(); !errors.Is(err, constant.ErrCondition) {
+		t.Errorf("map: expected ErrCondition, got %v", err)
+	}
+	if _, err := db.Table("users").Where(&expr.Eq{"id": 1}).Update(map[string]any{"name": "x"}); !errors.Is(err, constant.ErrCondition) {
+		t.Errorf("*expr.Eq: expected ErrCondition, got %v", err)
+	}
+}
+
+func TestQuery_UpdateDeleteWithoutWhere(t *testing.T) {
+	db, mock := newMockDB(t, "mysql")
+
+	if _, err := db.Table("users").Delete(); !errors.Is(err, constant.ErrNoWhere) {
+		t.Errorf("Delete: expected ErrNoWhere, got %v", err)
+	}
+	if _, err := db.Table("users").Where(expr.And{}).Update(map[string]any{"name": "x"}); !errors.Is(err, constant.ErrNoWhere) {
+		t.Errorf("Update: expected ErrNoWhere, got %v", err)
+	}
+
+	mock.ExpectExec(`UPDATE users SET active = \?`).
+		WithArgs(false).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`DELETE FROM users`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	if _, err := db.Table("users").AllRows().Update(map[string]any{"active": false}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := db.Table("users").AllRows().Delete(); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_UnsupportedSource(t *testing.T) {
+	db, _ := newMockDB(t, "mysql")
+
+	var users []User
+	if err := db.Table("users").From(42).Get(&users); !errors.Is(err, constant.ErrSource) {
+		t.Errorf("From: expected ErrSource, got %v", err)
+	}
+	if err := db.Table("users").Join(expr.Eq{"id": 1}, "1 = 1").Get(&users); !errors.Is(err, constant.ErrSource) {
+		t.Errorf("Join: expected ErrSource, got %v", err)
+	}
+}
+
+func TestQuery_WithNamed(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`WITH recent AS \(SELECT id FROM orders WHERE user_id = \$1 AND created_at > \$2::date\) SELECT \* FROM recent WHERE id > \$3`).
+		WithArgs(7, "2024-01-01", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+
+	var ids []int
+	err := db.Table("recent").
+		With("recent", "SELECT id FROM orders WHERE user_id = :user AND created_at > :since::date", Named{"user": 7, "since": "2024-01-01"}).
+		Where("id > ?", 10).
+		Get(&ids)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int{11}) {
+		t.Errorf("ids = %v, want [11]", ids)
+	}
+
+	err = db.Table("recent").With("recent", "SELECT id FROM orders WHERE user_id = :user", Named{}).Get(&ids)
+	if !errors.Is(err, constant.ErrNamedParam) {
+		t.Errorf("expected ErrNamedParam, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
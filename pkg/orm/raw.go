@@ -0,0 +1,95 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+)
+
+// RawQuery is a hand-written SQL query. Its placeholders are rebound per
+// driver and its rows are scanned with the same struct mapping as Query.Get.
+type RawQuery struct {
+	sql      string
+	args     []any
+	strict   bool
+	ctx      context.Context
+	err      error
+	executor *executorWrapper
+}
+
+// Raw initializes a raw SQL query. args are positional ? values, or a single
+// Named map or struct for :name parameters.
+func (db *DB) Raw(query string, args ...any) *RawQuery {
+	query, args, err := db.executor.expandNamed(query, args)
+	return &RawQuery{
+		sql:      query,
+		args:     args,
+		err:      err,
+		executor: db.executor,
+	}
+}
+
+// WithContext binds ctx to the raw query
+func (r *RawQuery) WithContext(ctx context.Context) *RawQuery {
+	r.ctx = ctx
+	return r
+}
+
+// Strict makes the raw query scan strictly even when its DB does not, see
+// DB.SetStrict.
+func (r *RawQuery) Strict() *RawQuery {
+	r.strict = true
+	return r
+}
+
+// ToSQL returns the raw query and its arguments, so it can be used as a
+// subquery inside expr conditions or as a CTE body in Query.With.
+func (r *RawQuery) ToSQL() (string, []any) {
+	return r.sql, r.args
+}
+
+// Err returns the error raised while building the raw query, such as a
+// missing named parameter. Queries embedding it fail with the same error.
+func (r *RawQuery) Err() error {
+	return r.err
+}
+
+// Scan runs the query and scans its rows into dest, like Query.Get.
+func (r *RawQuery) Scan(dest any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	rows, err := r.executor.query(ctx, r.sql, r.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	s := r.executor.scanner
+	s.Strict = s.Strict || r.strict
+	return s.Scan(rows, dest)
+}
+
+// ScanContext is like Scan but runs the query bound to ctx.
+func (r *RawQuery) ScanContext(ctx context.Context, dest any) error {
+	return r.WithContext(ctx).Scan(dest)
+}
+
+// Exec runs a raw SQL statement that returns no rows. args are positional ?
+// values, or a single Named map or struct for :name parameters.
+func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext is like Exec but runs the statement bound to ctx.
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	query, args, err := db.executor.expandNamed(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return db.executor.execute(ctx, query, args...)
+}
@@ -0,0 +1,150 @@
+package orm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/i-sub135/i-sub-orm/internal/constant"
+	"github.com/i-sub135/i-sub-orm/internal/expr"
+)
+
+func TestRaw_Scan(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT id, name FROM users WHERE name LIKE \$1 AND note != '\?' LIMIT \$2`).
+		WithArgs("jo%", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+
+	var users []User
+	if err := db.Raw("SELECT id, name FROM users WHERE name LIKE ? AND note != '?' LIMIT ?", "jo%", 10).Scan(&users); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "John Doe" {
+		t.Errorf("users mismatch: %+v", users)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRaw_Named(t *testing.T) {
+	db, mock := newMockDB(t, "sqlserver")
+
+	mock.ExpectQuery(`SELECT \* FROM users WHERE id = @p1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+
+	var user User
+	if err := db.Raw("SELECT * FROM users WHERE id = :id", Named{"id": 1}).Scan(&user); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if user.ID != 1 {
+		t.Errorf("user mismatch: %+v", user)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRaw_Subquery(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT \* FROM users WHERE id IN \(SELECT user_id FROM bans WHERE until > \$1\)`).
+		WithArgs("2024-01-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	banned := db.Raw("SELECT user_id FROM bans WHERE until > ?", "2024-01-01")
+
+	var users []User
+	if err := db.Table("users").Where(expr.In{"id": {banned}}).Get(&users); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRaw_SubqueryErr(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	banned := db.Raw("SELECT user_id FROM bans WHERE until > :until", Named{"since": "2024-01-01"})
+	if !errors.Is(banned.Err(), constant.ErrNamedParam) {
+		t.Fatalf("Err() = %v, want ErrNamedParam", banned.Err())
+	}
+
+	tests := map[string]*Query{
+		"in":  db.Table("users").Where(expr.In{"id": {banned}}),
+		"eq":  db.Table("users").Where(expr.Eq{"id": banned}),
+		"any": db.Table("users").Where(expr.Any{"id": banned}),
+		"cte": db.Table("banned").With("banned", banned),
+	}
+	for name, q := range tests {
+		t.Run(name, func(t *testing.T) {
+			var users []User
+			if err := q.Get(&users); !errors.Is(err, constant.ErrNamedParam) {
+				t.Errorf("Get() error = %v, want ErrNamedParam", err)
+			}
+		})
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRaw_CTE(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`WITH banned AS \(SELECT user_id FROM bans WHERE until > \$1\) SELECT \* FROM banned`).
+		WithArgs("2024-01-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	banned := db.Raw("SELECT user_id FROM bans WHERE until > :until", Named{"until": "2024-01-01"})
+
+	var users []User
+	if err := db.Table("banned").With("banned", banned).Get(&users); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExec(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectExec(`UPDATE users SET name = \$1 WHERE id = \$2`).
+		WithArgs("Jane Doe", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := db.Exec("UPDATE users SET name = :name WHERE id = :id", User{ID: 1, Name: "Jane Doe"})
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		t.Errorf("expected 1 row affected, got %d", n)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRaw_Strict(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectQuery(`SELECT id, nmae FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nmae"}).AddRow(1, "John Doe"))
+	mock.ExpectQuery(`SELECT id, nmae FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nmae"}).AddRow(1, "John Doe"))
+
+	var users []User
+	if err := db.Raw("SELECT id, nmae FROM users").Scan(&users); err != nil {
+		t.Fatalf("expected lenient scan by default, got %v", err)
+	}
+	if err := db.Raw("SELECT id, nmae FROM users").Strict().Scan(&users); !errors.Is(err, constant.ErrStrictScan) {
+		t.Errorf("expected ErrStrictScan, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,143 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/i-sub135/i-sub-orm/internal/driver"
+)
+
+// Tx is a database transaction. It exposes the same query API as DB; every
+// statement built from it runs inside the transaction.
+type Tx struct {
+	db    *DB
+	depth int // savepoint nesting level, 0 for the outermost transaction
+}
+
+// Transaction runs fn inside a transaction. The transaction is committed
+// when fn returns nil and rolled back when fn returns an error or panics.
+func (db *DB) Transaction(fn func(tx *Tx) error) error {
+	return db.TransactionContext(context.Background(), fn)
+}
+
+// TransactionContext is like Transaction but begins the transaction bound to ctx.
+func (db *DB) TransactionContext(ctx context.Context, fn func(tx *Tx) error) (err error) {
+	exec, err := db.executor.exec.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	tx := &Tx{db: &DB{executor: db.executor.withExecutor(exec)}}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = exec.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := exec.Rollback(); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return exec.Commit()
+}
+
+// Table initializes a new query for the specified table inside the transaction
+func (tx *Tx) Table(name string) *Query {
+	return tx.db.Table(name)
+}
+
+// From initializes a new query reading from source inside the transaction
+func (tx *Tx) From(source any) *Query {
+	return tx.db.From(source)
+}
+
+// Raw initializes a raw SQL query inside the transaction, see DB.Raw.
+func (tx *Tx) Raw(query string, args ...any) *RawQuery {
+	return tx.db.Raw(query, args...)
+}
+
+// Exec runs a raw SQL statement inside the transaction, see DB.Exec.
+func (tx *Tx) Exec(query string, args ...any) (sql.Result, error) {
+	return tx.db.Exec(query, args...)
+}
+
+// ExecContext is like Exec but runs the statement bound to ctx.
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return tx.db.ExecContext(ctx, query, args...)
+}
+
+// Create inserts model inside the transaction, see DB.Create.
+func (tx *Tx) Create(model any) error {
+	return tx.db.Create(model)
+}
+
+// CreateContext is like Create but runs the insert bound to ctx.
+func (tx *Tx) CreateContext(ctx context.Context, model any) error {
+	return tx.db.CreateContext(ctx, model)
+}
+
+// Transaction runs fn in a nested transaction backed by a savepoint. An
+// error or panic from fn rolls back to the savepoint only, leaving the
+// outer transaction usable.
+func (tx *Tx) Transaction(fn func(tx *Tx) error) error {
+	return tx.TransactionContext(context.Background(), fn)
+}
+
+// TransactionContext is like Transaction but runs the savepoint statements bound to ctx.
+func (tx *Tx) TransactionContext(ctx context.Context, fn func(tx *Tx) error) (err error) {
+	nested := &Tx{db: tx.db, depth: tx.depth + 1}
+	name := fmt.Sprintf("sp_%d", nested.depth)
+
+	if err := tx.savepoint(ctx, "create", name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.savepoint(ctx, "rollback", name)
+			panic(p)
+		}
+	}()
+
+	if err := fn(nested); err != nil {
+		if rbErr := tx.savepoint(ctx, "rollback", name); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return tx.savepoint(ctx, "release", name)
+}
+
+// savepoint creates, rolls back to or releases the named savepoint using
+// the driver's syntax.
+func (tx *Tx) savepoint(ctx context.Context, action, name string) error {
+	mssql := driver.Driver(tx.db.executor.driver) == driver.MSSQL
+
+	var query string
+	switch action {
+	case "create":
+		query = "SAVEPOINT " + name
+		if mssql {
+			query = "SAVE TRANSACTION " + name
+		}
+	case "rollback":
+		query = "ROLLBACK TO SAVEPOINT " + name
+		if mssql {
+			query = "ROLLBACK TRANSACTION " + name
+		}
+	case "release":
+		// SQL Server has no RELEASE; savepoints end with the transaction
+		if mssql {
+			return nil
+		}
+		query = "RELEASE SAVEPOINT " + name
+	}
+
+	_, err := tx.db.executor.execute(ctx, query)
+	return err
+}
@@ -0,0 +1,118 @@
+package orm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestTransaction_Commit(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM users WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := db.Transaction(func(tx *Tx) error {
+		_, err := tx.Table("users").Where("id = ?", 1).Delete()
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTransaction_RollbackOnError(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+	errBoom := errors.New("boom")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err := db.Transaction(func(tx *Tx) error {
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("expected errBoom, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTransaction_RollbackOnPanic(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("expected panic to propagate, got %v", p)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	_ = db.Transaction(func(tx *Tx) error {
+		panic("boom")
+	})
+}
+
+func TestTransaction_NestedSavepoints(t *testing.T) {
+	db, mock := newMockDB(t, "postgres")
+	errBoom := errors.New("boom")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`RELEASE SAVEPOINT sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := db.Transaction(func(tx *Tx) error {
+		if err := tx.Transaction(func(tx *Tx) error { return nil }); err != nil {
+			return err
+		}
+		if err := tx.Transaction(func(tx *Tx) error { return errBoom }); !errors.Is(err, errBoom) {
+			t.Errorf("expected errBoom from nested transaction, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTransaction_NestedSavepointsMSSQL(t *testing.T) {
+	db, mock := newMockDB(t, "sqlserver")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVE TRANSACTION sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVE TRANSACTION sp_2`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ROLLBACK TRANSACTION sp_2`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := db.Transaction(func(tx *Tx) error {
+		return tx.Transaction(func(tx *Tx) error {
+			_ = tx.Transaction(func(tx *Tx) error { return errors.New("boom") })
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}